@@ -0,0 +1,258 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jezek/goMDID/dataset"
+	"github.com/jezek/goMDID/metrics"
+)
+
+// Returns new flag set for subcommand name with usage printing command description and flags.
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags]%s\n\nFlags:\n", os.Args[0], name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// Compares dataset provided metrics with MOS.
+func runEvaluate(args []string) error {
+	fs := newFlagSet("evaluate", "")
+	datasetDir := fs.String("dataset", defaultDatasetDir, "dataset directory")
+	datasetFormat := fs.String("format", defaultDatasetFormat, "dataset format: "+strings.Join(dataset.LoaderNames(), ", "))
+	metricsFlag := fs.String("metrics", defaultProvidedMetrics, "comma separated list of provided metrics")
+	evaluatorsFlag := fs.String("evaluators", defaultEvaluators, "comma separated list of evaluators")
+	tableOpts := addTableFlags(fs)
+	tableOpts.addSignificanceFlags(fs)
+	plotOpts := addPlotFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	evaluatorsList, err := parseEvaluators(*evaluatorsFlag)
+	if err != nil {
+		return err
+	}
+	ds, err := loadDataset(*datasetFormat, *datasetDir)
+	if err != nil {
+		return err
+	}
+
+	printGroupedTables(ds, "Comparing dataset MOS to provided metrics (pm) rankings using different evaluators (ev):", "pm\\ev", splitList(*metricsFlag), evaluatorsList, func(ds dataset.Dataset, pm string) ([]float64, []float64) {
+		return ds.ProvidedMetricsByName("mos"), orient(pm, ds.ProvidedMetricsByName(pm))
+	}, tableOpts)
+	return writePlots(ds, splitList(*metricsFlag), func(ds dataset.Dataset, pm string) ([]float64, []float64) {
+		return ds.ProvidedMetricsByName("mos"), ds.ProvidedMetricsByName(pm)
+	}, plotOpts)
+}
+
+// Computes metrics on dataset and compares them with MOS.
+func runCompute(args []string) error {
+	fs := newFlagSet("compute", "")
+	datasetDir := fs.String("dataset", defaultDatasetDir, "dataset directory")
+	datasetFormat := fs.String("format", defaultDatasetFormat, "dataset format: "+strings.Join(dataset.LoaderNames(), ", "))
+	metricsFlag := fs.String("metrics", defaultComputedMetrics, "comma separated list of computed metrics")
+	evaluatorsFlag := fs.String("evaluators", defaultEvaluators, "comma separated list of evaluators")
+	tableOpts := addTableFlags(fs)
+	tableOpts.addSignificanceFlags(fs)
+	plotOpts := addPlotFlags(fs)
+	workers := fs.Int("workers", defaultWorkers, "number of metrics computed in parallel")
+	storeName := fs.String("store", dataset.DefaultStoreName, "file storing computed metrics (relative to dataset directory), values found in it are not recomputed, empty for no store")
+	ssimMapsDistortion := fs.String("ssim-maps", "", "distorted image file name (e.g. \"img01_3_CC1_JPEG2.bmp\") for which SSIM maps are written as PNG images")
+	ssimMapsDir := fs.String("ssim-maps-dir", "ssim_maps", "directory for SSIM maps")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	evaluatorsList, err := parseEvaluators(*evaluatorsFlag)
+	if err != nil {
+		return err
+	}
+	metricsList, err := parseMetrics(*metricsFlag)
+	if err != nil {
+		return err
+	}
+	ds, err := loadDataset(*datasetFormat, *datasetDir)
+	if err != nil {
+		return err
+	}
+
+	if err := computeMetrics(ds, metricsList, *workers, storePath(*datasetDir, *storeName)); err != nil {
+		return err
+	}
+
+	// Write SSIM maps for chosen distortion.
+	if *ssimMapsDistortion != "" {
+		if ref, dis, ok := ds.DistortionByName(*ssimMapsDistortion); ok {
+			if err := writeSSIMMaps(ref, dis, *ssimMapsDir); err != nil {
+				log.Printf("Could not write SSIM maps: %v", err)
+			}
+		} else {
+			log.Printf("No distortion %s in dataset", *ssimMapsDistortion)
+		}
+	}
+
+	printGroupedTables(ds, "Comparing dataset MOS to computed metrics (cm) rankings using different evaluators (ev):", "cm\\ev", metricsList, evaluatorsList, func(ds dataset.Dataset, cm string) ([]float64, []float64) {
+		return ds.ProvidedMetricsByName("mos"), orient(cm, ds.ComputedMetricsByName(cm))
+	}, tableOpts)
+	return writePlots(ds, metricsList, func(ds dataset.Dataset, cm string) ([]float64, []float64) {
+		return ds.ProvidedMetricsByName("mos"), ds.ComputedMetricsByName(cm)
+	}, plotOpts)
+}
+
+// Compares dataset provided metrics with computed metrics.
+func runCompare(args []string) error {
+	fs := newFlagSet("compare", "")
+	datasetDir := fs.String("dataset", defaultDatasetDir, "dataset directory")
+	datasetFormat := fs.String("format", defaultDatasetFormat, "dataset format: "+strings.Join(dataset.LoaderNames(), ", "))
+	metricsFlag := fs.String("metrics", defaultComparedMetrics, "comma separated list of provided metrics to compare, optionally with computed metric name after colon if it differs (e.g. \"VIF:VIFp\")")
+	evaluatorsFlag := fs.String("evaluators", defaultEvaluators, "comma separated list of evaluators")
+	tableOpts := addTableFlags(fs)
+	workers := fs.Int("workers", defaultWorkers, "number of metrics computed in parallel")
+	storeName := fs.String("store", dataset.DefaultStoreName, "file storing computed metrics (relative to dataset directory), values found in it are not recomputed, empty for no store")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	evaluatorsList, err := parseEvaluators(*evaluatorsFlag)
+	if err != nil {
+		return err
+	}
+
+	// Pairs of provided and computed metrics names to compare.
+	rows, computed, computeList := []string{}, map[string][2]string{}, []string{}
+	for _, item := range splitList(*metricsFlag) {
+		pm, cm := item, item
+		if i := strings.Index(item, ":"); i >= 0 {
+			pm, cm = item[:i], item[i+1:]
+		}
+		if _, err := parseMetrics(cm); err != nil {
+			return err
+		}
+		row := pm
+		if pm != cm {
+			row = pm + "/" + cm
+		}
+		rows = append(rows, row)
+		computed[row] = [2]string{pm, cm}
+		computeList = append(computeList, cm)
+	}
+
+	ds, err := loadDataset(*datasetFormat, *datasetDir)
+	if err != nil {
+		return err
+	}
+
+	if err := computeMetrics(ds, computeList, *workers, storePath(*datasetDir, *storeName)); err != nil {
+		return err
+	}
+
+	printGroupedTables(ds, "Comparing provided metrics to computed metrics (m) using different evaluators (ev):", "m\\ev", rows, evaluatorsList, func(ds dataset.Dataset, row string) ([]float64, []float64) {
+		m := computed[row]
+		return ds.ProvidedMetricsByName(m[0]), ds.ComputedMetricsByName(m[1])
+	}, tableOpts)
+	return nil
+}
+
+// Computes metrics for single reference and distorted image pair.
+func runScore(args []string) error {
+	fs := newFlagSet("score", " <reference image> <distorted image>")
+	metricsFlag := fs.String("metrics", defaultComputedMetrics, "comma separated list of computed metrics")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected reference and distorted image paths, got %d arguments", fs.NArg())
+	}
+
+	metricsList, err := parseMetrics(*metricsFlag)
+	if err != nil {
+		return err
+	}
+
+	refImg, err := dataset.ImageFromPath(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("loading reference image error: %w", err)
+	}
+	disImg, err := dataset.ImageFromPath(fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("loading distorted image error: %w", err)
+	}
+	if !refImg.Bounds().Eq(disImg.Bounds()) {
+		return fmt.Errorf("images dimensions not equal: %v, %v", refImg.Bounds(), disImg.Bounds())
+	}
+
+	for _, m := range metricsList {
+		info, _ := metrics.Lookup(m)
+		v, err := info.Metric.Compute(refImg, disImg)
+		if err != nil {
+			return fmt.Errorf("computing metric %s error: %w", m, err)
+		}
+		fmt.Printf("%10s%10f\n", m, v)
+	}
+	return nil
+}
+
+// Exports dataset with provided and computed metrics to CSV file.
+func runExport(args []string) error {
+	fs := newFlagSet("export", "")
+	datasetDir := fs.String("dataset", defaultDatasetDir, "dataset directory")
+	datasetFormat := fs.String("format", defaultDatasetFormat, "dataset format: "+strings.Join(dataset.LoaderNames(), ", "))
+	metricsFlag := fs.String("metrics", defaultComputedMetrics, "comma separated list of computed metrics, empty for none")
+	workers := fs.Int("workers", defaultWorkers, "number of metrics computed in parallel")
+	storeName := fs.String("store", dataset.DefaultStoreName, "file storing computed metrics (relative to dataset directory), values found in it are not recomputed, empty for no store")
+	output := fs.String("o", "dataset.csv", "output file, \"-\" for standart output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	metricsList, err := parseMetrics(*metricsFlag)
+	if err != nil {
+		return err
+	}
+	ds, err := loadDataset(*datasetFormat, *datasetDir)
+	if err != nil {
+		return err
+	}
+	if len(metricsList) > 0 {
+		if err := computeMetrics(ds, metricsList, *workers, storePath(*datasetDir, *storeName)); err != nil {
+			return err
+		}
+	}
+
+	if *output == "-" {
+		return ds.Export(os.Stdout)
+	}
+	if err := ds.ExportFile(*output); err != nil {
+		return fmt.Errorf("exporting dataset error: %w", err)
+	}
+	log.Printf("Dataset exported to %s", *output)
+	return nil
+}
+
+// Lists available metrics with their properties.
+func runMetrics(args []string) error {
+	fs := newFlagSet("metrics", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Printf("%-10s%-12s%-18s%-16s%-7s%s\n", "name", "aliases", "direction", "range", "color", "default config")
+	for _, info := range metrics.Registered() {
+		config := []string{}
+		for k, v := range info.Config {
+			config = append(config, fmt.Sprintf("%s=%g", k, v))
+		}
+		sort.Strings(config)
+		fmt.Printf("%-10s%-12s%-18s%-16s%-7s%s\n", info.Name, strings.Join(info.Aliases, ","), info.Direction, fmt.Sprintf("[%g, %g]", info.Min, info.Max), info.Color, strings.Join(config, " "))
+	}
+	return nil
+}
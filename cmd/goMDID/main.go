@@ -0,0 +1,594 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/jezek/goMDID/dataset"
+	"github.com/jezek/goMDID/metrics"
+	"github.com/jezek/goMDID/stats"
+	"github.com/jezek/goMDID/table"
+)
+
+// MDID dataset (https://www.sz.tsinghua.edu.cn/labs/vipl/mdid.html) image similarity metrics, rewritten to go (golang)
+
+// Evaluators available for comparing metrics.
+var evaluators = map[string]stats.Evaluator{
+	"SROCC":   stats.EvaluatorFunc(stats.SROCCErr),
+	"SROCCos": checkedEvaluator(stats.SROCConlinestats),
+	"SROCCgs": checkedEvaluator(stats.SROCCgostats),
+	"KROCC":   stats.EvaluatorFunc(stats.KROCCErr),
+	"KROCCkn": stats.EvaluatorFunc(stats.KROCCknightErr),
+	"KROCCgn": checkedEvaluator(stats.KROCCgonum),
+	"KROCCgs": checkedEvaluator(stats.KROCCgostats),
+	"PLCC":    stats.EvaluatorFunc(stats.PLCCErr),
+	"PLCCgn":  checkedEvaluator(stats.PLCCgonum),
+	"PLCCgs":  checkedEvaluator(stats.PLCCgostats),
+	"RMSE":    stats.EvaluatorFunc(stats.RMSEErr),
+
+	"PLCC-fitted":  stats.EvaluatorFunc(stats.PLCCFittedErr),
+	"RMSE-fitted":  stats.EvaluatorFunc(stats.RMSEFittedErr),
+	"PLCC-fitted4": stats.EvaluatorFunc(stats.PLCCFitted4Err),
+	"RMSE-fitted4": stats.EvaluatorFunc(stats.RMSEFitted4Err),
+}
+
+// Returns evaluator for function f without error returning variant (third party implementations), which returns stats.ErrLengthMismatch instead of calling f with inputs of different lengths.
+func checkedEvaluator(f func([]float64, []float64) float64) stats.Evaluator {
+	return stats.EvaluatorFunc(func(a, b []float64) (float64, error) {
+		if len(a) != len(b) {
+			return 0, stats.ErrLengthMismatch
+		}
+		return f(a, b), nil
+	})
+}
+
+// Returns function evaluating inputs using evaluator ev, which returns NaN if ev returns error.
+func nanOnError(ev stats.Evaluator) func([]float64, []float64) float64 {
+	return func(a, b []float64) float64 {
+		v, err := ev.Evaluate(a, b)
+		if err != nil {
+			return math.NaN()
+		}
+		return v
+	}
+}
+
+// Evaluators for which lower value means better agreement.
+var lowerIsBetterEvaluators = map[string]bool{
+	"RMSE":         true,
+	"RMSE-fitted":  true,
+	"RMSE-fitted4": true,
+}
+
+// Logistic models used by evaluators, which map metrics values before evaluation.
+var evaluatorModels = map[string]stats.LogisticModel{
+	"PLCC-fitted":  stats.Logistic5,
+	"RMSE-fitted":  stats.Logistic5,
+	"PLCC-fitted4": stats.Logistic4,
+	"RMSE-fitted4": stats.Logistic4,
+}
+
+// Default flag values.
+const (
+	defaultDatasetDir      = "dataset/MDID"
+	defaultDatasetFormat   = "MDID"
+	defaultEvaluators      = "SROCC,KROCC,PLCC,RMSE"
+	defaultProvidedMetrics = "PSNR,SSIM,VIF,IWSSIM,FSIMc,GMSD"
+	defaultComputedMetrics = "PSNRg,PSNR,SSIM,MSSSIM,VIFp,IWSSIM,FSIMc,GMSD"
+	defaultComparedMetrics = "PSNR,SSIM,VIF:VIFp,IWSSIM,FSIMc,GMSD"
+)
+
+// Default number of workers computing metrics in parallel.
+var defaultWorkers = runtime.NumCPU()
+
+// Subcommands with their descriptions and run functions.
+var commands = []struct {
+	name, description string
+	run               func(args []string) error
+}{
+	{"evaluate", "compare dataset provided metrics with MOS", runEvaluate},
+	{"compute", "compute metrics on dataset and compare them with MOS", runCompute},
+	{"compare", "compare dataset provided metrics with computed metrics", runCompare},
+	{"score", "compute metrics for single reference and distorted image pair", runScore},
+	{"export", "export dataset with provided and computed metrics to CSV file", runExport},
+	{"metrics", "list available metrics", runMetrics},
+	{"report", "compute metrics on dataset, evaluate them and write HTML report", runReport},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n", filepath.Base(os.Args[0]))
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for command flags.\n", filepath.Base(os.Args[0]))
+}
+
+// Runs subcommand given by first argument.
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("log: ")
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+		if err := c.run(os.Args[2:]); err != nil {
+			if err == flag.ErrHelp {
+				os.Exit(0)
+			}
+			log.Fatalf("%s: %v", c.name, err)
+		}
+		return
+	}
+
+	if os.Args[1] == "-h" || os.Args[1] == "-help" || os.Args[1] == "help" {
+		usage()
+		return
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
+
+// Returns non empty, trimmed items of comma separated list s.
+func splitList(s string) []string {
+	res := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// Returns error if some of names is not in available names.
+func checkNames(kind string, names, available []string) error {
+	for _, n := range names {
+		found := false
+		for _, a := range available {
+			if a == n {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown %s %q, available: %s", kind, n, strings.Join(available, ", "))
+		}
+	}
+	return nil
+}
+
+// Returns sorted names of available evaluators.
+func evaluatorNames() []string {
+	res := make([]string, 0, len(evaluators))
+	for n := range evaluators {
+		res = append(res, n)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Returns sorted names of available (registered) metrics.
+func metricNames() []string {
+	res := []string{}
+	for _, info := range metrics.Registered() {
+		res = append(res, info.Name)
+	}
+	return res
+}
+
+// Returns evaluators list parsed from flag value, checking if all evaluators are available.
+func parseEvaluators(value string) ([]string, error) {
+	list := splitList(value)
+	if err := checkNames("evaluator", list, evaluatorNames()); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Returns computed metrics list parsed from flag value, checking if all metrics are available by name or alias.
+func parseMetrics(value string) ([]string, error) {
+	list := splitList(value)
+	for _, m := range list {
+		if _, ok := metrics.Lookup(m); !ok {
+			return nil, fmt.Errorf("unknown metric %q, available: %s", m, strings.Join(metricNames(), ", "))
+		}
+	}
+	return list, nil
+}
+
+// Returns values of metric name oriented so, that higher value means better quality.
+// Values of registered lower-is-better metrics are negated, values of other metrics (including unregistered ones) are returned unchanged.
+func orient(name string, values []float64) []float64 {
+	if info, ok := metrics.Lookup(name); !ok || info.Direction != metrics.LowerIsBetter {
+		return values
+	}
+	res := make([]float64, len(values))
+	for i, v := range values {
+		res[i] = -v
+	}
+	return res
+}
+
+// Returns path of metrics store file name in dataset directory dir, or empty string if name is empty.
+func storePath(dir, name string) string {
+	if name == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
+
+// Loads dataset in format (dataset loader name) from directory.
+func loadDataset(format, dir string) (dataset.Dataset, error) {
+	loader, ok := dataset.Loaders[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown dataset format %q, available: %s", format, strings.Join(dataset.LoaderNames(), ", "))
+	}
+	ds, err := loader.Load(dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s dataset from \"%s\" error: %w", format, dir, err)
+	}
+	return ds, nil
+}
+
+// Options of printed evaluation tables.
+type tableOptions struct {
+	// If true, pairs containing NaN values are dropped before evaluation.
+	pairwiseComplete bool
+	// Bootstrap confidence intervals configuration, zero resamples for no intervals.
+	bootstrap stats.BootstrapConfig
+	// Significance tests for comparing rows (metrics) and their significance level.
+	significance []string
+	alpha        float64
+	// Dataset groupings, tables are printed also for every group of each grouping.
+	groupings []string
+	// Renderer of tables, its options and output (standart output if nil).
+	renderer      table.Renderer
+	renderOptions table.Options
+	out           io.Writer
+}
+
+// Renders table t to output using renderer from options.
+func (o *tableOptions) render(t *table.Table) {
+	out := o.out
+	if out == nil {
+		out = os.Stdout
+	}
+	if err := o.renderer.Render(out, t, o.renderOptions); err != nil {
+		log.Printf("Could not render table: %v", err)
+	}
+}
+
+// Significance tests available for comparing metrics.
+var significanceTests = map[string]stats.SignificanceTest{
+	"f":        stats.FTestResiduals,
+	"williams": stats.Williams,
+	"steiger":  stats.Steiger,
+	"fisher":   stats.FisherZ,
+}
+
+// Dataset groupings available for printing tables per group.
+var groupings = map[string]func(dataset.Dataset) []dataset.Group{
+	"reference":  dataset.Dataset.GroupByReference,
+	"distortion": dataset.Dataset.GroupByDistortionKind,
+	"count":      dataset.Dataset.GroupByDistortionCount,
+}
+
+// Returns table options with values set by flags added to fs.
+func addTableFlags(fs *flag.FlagSet) *tableOptions {
+	o := addEvaluationFlags(fs)
+	o.addOutputFlags(fs)
+	return o
+}
+
+// Returns table options with evaluation and grouping values set by flags added to fs, tables are rendered as text.
+func addEvaluationFlags(fs *flag.FlagSet) *tableOptions {
+	o := &tableOptions{pairwiseComplete: true, bootstrap: stats.DefaultBootstrapConfig, renderer: table.Renderers["text"]}
+	o.bootstrap.Resamples = 0
+	fs.BoolVar(&o.pairwiseComplete, "pairwise-complete", o.pairwiseComplete, "drop pairs with missing (NaN) values before evaluation and report their count, otherwise missing values propagate to evaluators")
+	fs.IntVar(&o.bootstrap.Resamples, "bootstrap", o.bootstrap.Resamples, "number of bootstrap resamples for evaluators confidence intervals, 0 for none")
+	fs.Float64Var(&o.bootstrap.Level, "ci-level", o.bootstrap.Level, "confidence level of bootstrap confidence intervals")
+	fs.Int64Var(&o.bootstrap.Seed, "seed", o.bootstrap.Seed, "seed for bootstrap resampling")
+	fs.Func("ci-method", "bootstrap confidence intervals method, \"bca\" or \"percentile\" (default \"bca\")", func(v string) error {
+		switch strings.ToLower(v) {
+		case "bca":
+			o.bootstrap.Method = stats.BCa
+		case "percentile":
+			o.bootstrap.Method = stats.Percentile
+		default:
+			return fmt.Errorf("unknown method %q", v)
+		}
+		return nil
+	})
+	fs.Func("group", "comma separated list of dataset groupings, tables are printed also for every group: reference (reference image), distortion (contained distortion type), count (number of distortions)", func(v string) error {
+		list := splitList(v)
+		for _, g := range list {
+			if _, ok := groupings[g]; !ok {
+				return fmt.Errorf("unknown grouping %q", g)
+			}
+		}
+		o.groupings = list
+		return nil
+	})
+	return o
+}
+
+// Adds flags for tables output format to fs, setting values to o.
+func (o *tableOptions) addOutputFlags(fs *flag.FlagSet) {
+	fs.Func("output", "tables output format: "+strings.Join(table.RendererNames(), ", ")+" (default \"text\")", func(v string) error {
+		r, ok := table.Renderers[v]
+		if !ok {
+			return fmt.Errorf("unknown output format %q", v)
+		}
+		o.renderer = r
+		return nil
+	})
+	fs.BoolVar(&o.renderOptions.BoldBest, "bold-best", false, "emphasize the best value in each column of evaluation tables (asterisk in text output)")
+	fs.BoolVar(&o.renderOptions.CSVTitles, "csv-titles", false, "write table titles as \"# \" comment lines and empty line after each table in CSV output")
+}
+
+// Adds flags for significance tests between metrics to fs, setting values to o.
+// Significance tests can be used only if all table rows share the same subjective scores.
+func (o *tableOptions) addSignificanceFlags(fs *flag.FlagSet) {
+	o.alpha = 0.05
+	fs.Func("significance", "comma separated list of significance tests between metrics after logistic fitting: f (F-test of residuals), williams, steiger, fisher", func(v string) error {
+		list := splitList(v)
+		for _, t := range list {
+			if _, ok := significanceTests[t]; !ok {
+				return fmt.Errorf("unknown test %q", t)
+			}
+		}
+		o.significance = list
+		return nil
+	})
+	fs.Float64Var(&o.alpha, "alpha", o.alpha, "significance level of significance tests")
+}
+
+// Prints table with rows and evaluatorsList columns, cell values are values of evaluator applied to data(row).
+// If some of evaluators fits logistic function, table with fitted parameters follows.
+// If bootstrap is enabled in options, tables with confidence intervals and standart errors of evaluators follow.
+// If significance tests are set in options, significance matrices of rows follow.
+func printTable(title, corner string, rows, evaluatorsList []string, data func(row string) (a, b []float64), options *tableOptions) {
+	dropped, allData := map[string]int{}, data
+	if options.pairwiseComplete {
+		data = func(row string) ([]float64, []float64) {
+			a, b, n := stats.PairwiseComplete(allData(row))
+			dropped[row] = n
+			return a, b
+		}
+	}
+
+	columns := []table.Column{}
+	for _, em := range evaluatorsList {
+		best := table.HighestBest
+		if lowerIsBetterEvaluators[em] {
+			best = table.LowestBest
+		}
+		columns = append(columns, table.Column{Name: em, Best: best})
+	}
+	if options.pairwiseComplete {
+		columns = append(columns, table.Column{Name: "dropped", Format: "%.0f"})
+	}
+	t := table.New(title, corner, columns...)
+	for _, r := range rows {
+		a, b := data(r)
+		cells := []table.Cell{}
+		for _, em := range evaluatorsList {
+			v, err := evaluators[em].Evaluate(a, b)
+			if err != nil {
+				log.Printf("Could not evaluate %s using %s: %v", r, em, err)
+				v = math.NaN()
+			}
+			cells = append(cells, table.Num(v))
+		}
+		if options.pairwiseComplete {
+			cells = append(cells, table.Num(float64(dropped[r])))
+		}
+		t.AddRow(r, cells...)
+	}
+	options.render(t)
+
+	if options.bootstrap.Resamples > 0 {
+		printBootstrapTables(rows, evaluatorsList, data, options)
+	}
+
+	for _, test := range options.significance {
+		printSignificanceMatrix(test, rows, allData, options)
+	}
+
+	for _, model := range fittedModels(evaluatorsList) {
+		columns := []table.Column{}
+		for i := 0; i < model.NumParams; i++ {
+			columns = append(columns, table.Column{Name: fmt.Sprintf("β%d", i+1), Format: "%.6g"})
+		}
+		t := table.New(fmt.Sprintf("Fitted %s function parameters:", model.Name), "", columns...)
+		for _, r := range rows {
+			a, b := data(r)
+			fit, err := stats.FitLogistic(b, a, model)
+			if err != nil {
+				cells := []table.Cell{table.Text(err.Error())}
+				for i := 1; i < model.NumParams; i++ {
+					cells = append(cells, table.Text("-"))
+				}
+				t.AddRow(r, cells...)
+				continue
+			}
+			cells := []table.Cell{}
+			for _, p := range fit.Params {
+				cells = append(cells, table.Num(p))
+			}
+			t.AddRow(r, cells...)
+		}
+		options.render(t)
+	}
+}
+
+// Prints bootstrap confidence intervals, means (with estimates on original data) and standart errors of evaluators applied to data(row).
+func printBootstrapTables(rows, evaluatorsList []string, data func(row string) (a, b []float64), options *tableOptions) {
+	config := options.bootstrap
+	method := "BCa"
+	if config.Method == stats.Percentile {
+		method = "percentile"
+	}
+
+	columns := []table.Column{}
+	for _, em := range evaluatorsList {
+		columns = append(columns, table.Column{Name: em})
+	}
+	intervals := table.New(fmt.Sprintf("Bootstrap %g%% %s confidence intervals (%d resamples):", config.Level*100, method, config.Resamples), "", columns...)
+	means := table.New("Bootstrap means (estimate on original data in parentheses):", "", columns...)
+	stdErrs := table.New("Bootstrap standart errors:", "", columns...)
+	for _, r := range rows {
+		a, b := data(r)
+		intervalCells, meanCells, stdErrCells := []table.Cell{}, []table.Cell{}, []table.Cell{}
+		for _, em := range evaluatorsList {
+			res, err := stats.Bootstrap(nanOnError(evaluators[em]), a, b, config)
+			if err != nil {
+				intervalCells, meanCells, stdErrCells = append(intervalCells, table.Text("NaN")), append(meanCells, table.Text("NaN")), append(stdErrCells, table.Num(math.NaN()))
+				continue
+			}
+			intervalCells = append(intervalCells, table.Text(fmt.Sprintf("[%.4f, %.4f]", res.Lower, res.Upper)))
+			meanCells = append(meanCells, table.Text(fmt.Sprintf("%.4f (%.4f)", res.Mean, res.Estimate)))
+			stdErrCells = append(stdErrCells, table.Num(res.StdErr))
+		}
+		intervals.AddRow(r, intervalCells...)
+		means.AddRow(r, meanCells...)
+		stdErrs.AddRow(r, stdErrCells...)
+	}
+	options.render(intervals)
+	options.render(means)
+	options.render(stdErrs)
+}
+
+// Prints significance matrix of rows using test named t. Subjective scores are taken from data of first row.
+// Data are taken before dropping of pairs with NaN values, if pairwise complete option is set, values with NaN in any row are dropped.
+func printSignificanceMatrix(t string, rows []string, data func(row string) (a, b []float64), options *tableOptions) {
+	if len(rows) == 0 {
+		return
+	}
+	mos, _ := data(rows[0])
+	objective := make([][]float64, len(rows))
+	for i, r := range rows {
+		_, objective[i] = data(r)
+	}
+	dropped := 0
+	if options.pairwiseComplete {
+		var complete [][]float64
+		complete, dropped = stats.CompleteCases(append([][]float64{mos}, objective...)...)
+		mos, objective = complete[0], complete[1:]
+	}
+	alpha := options.alpha
+	signs, pvalues := stats.SignificanceMatrix(mos, objective, stats.Logistic5, significanceTests[t], alpha)
+
+	title := fmt.Sprintf("Significance matrix using %s test (alpha %g), 1 if row metric is significantly better than column metric, -1 if worse, 0 if not significant:", t, alpha)
+	if dropped > 0 {
+		title += fmt.Sprintf("\n(%d samples with missing values dropped)", dropped)
+	}
+	columns := []table.Column{}
+	for _, r := range rows {
+		columns = append(columns, table.Column{Name: r})
+	}
+	matrix := table.New(title, "", columns...)
+	for i, r := range rows {
+		cells := []table.Cell{}
+		for j := range rows {
+			cell := "-"
+			if i != j {
+				cell = fmt.Sprintf("%2d (p=%.4f)", signs[i][j], pvalues[i][j])
+			}
+			cells = append(cells, table.Text(cell))
+		}
+		matrix.AddRow(r, cells...)
+	}
+	options.render(matrix)
+}
+
+// Prints table (see printTable) for whole dataset ds and for every group of groupings set in options.
+func printGroupedTables(ds dataset.Dataset, title, corner string, rows, evaluatorsList []string, data func(ds dataset.Dataset, row string) (a, b []float64), options *tableOptions) {
+	printTable(title, corner, rows, evaluatorsList, func(row string) ([]float64, []float64) {
+		return data(ds, row)
+	}, options)
+
+	for _, g := range options.groupings {
+		for _, group := range groupings[g](ds) {
+			groupTitle := fmt.Sprintf("%s\nGroup by %s: %s (%d distorted images)", title, g, group.Name, group.Dataset.DistortedCount())
+			printTable(groupTitle, corner, rows, evaluatorsList, func(row string) ([]float64, []float64) {
+				return data(group.Dataset, row)
+			}, options)
+		}
+	}
+}
+
+// Returns logistic models used by fitted evaluators in evaluatorsList.
+func fittedModels(evaluatorsList []string) []stats.LogisticModel {
+	res := []stats.LogisticModel{}
+	for _, model := range []stats.LogisticModel{stats.Logistic4, stats.Logistic5} {
+		for _, em := range evaluatorsList {
+			if m, ok := evaluatorModels[em]; ok && m.Name == model.Name {
+				res = append(res, model)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Computes metrics from list for every distorted image in dataset using workers goroutines.
+// If storePath is not empty, values are loaded from store file on storePath and only missing values are computed and saved.
+func computeMetrics(ds dataset.Dataset, list []string, workers int, storePath string) error {
+	progress := func(done, total int) {
+		fmt.Fprintf(os.Stderr, "\rComputing metrics: %d/%d", done, total)
+	}
+	if storePath == "" {
+		ms := map[string]metrics.Metric{}
+		for _, m := range list {
+			info, _ := metrics.Lookup(m)
+			ms[m] = info.Metric
+		}
+		if err := ds.ComputeMetrics(ms, list, workers, progress); err != nil {
+			return err
+		}
+	} else {
+		store, err := dataset.OpenStore(storePath)
+		if err != nil {
+			return err
+		}
+		infos := map[string]metrics.Info{}
+		for _, m := range list {
+			infos[m], _ = metrics.Lookup(m)
+		}
+		if err := ds.ComputeMetricsStored(store, infos, list, workers, progress); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\rMetrics computed: %v%30s\n", list, "")
+	return nil
+}
+
+// Writes SSIM maps of distorted image dis against reference image ref to dir.
+func writeSSIMMaps(ref dataset.Reference, dis dataset.Distortion, dir string) error {
+	refImg, err := dataset.ImageFromPath(ref.Path)
+	if err != nil {
+		return fmt.Errorf("loading reference image error: %w", err)
+	}
+	disImg, err := dataset.ImageFromPath(dis.Path)
+	if err != nil {
+		return fmt.Errorf("loading distorted image error: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(dis.Path), filepath.Ext(dis.Path))
+	return metrics.WriteSSIMMaps(refImg, disImg, dir, name)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jezek/goMDID/dataset"
+	"github.com/jezek/goMDID/metrics"
+	"github.com/jezek/goMDID/plot"
+	"github.com/jezek/goMDID/stats"
+)
+
+// Point colorings of metric plots, returning group name of distorted image.
+var plotColorings = map[string]func(dis dataset.Distortion) string{
+	"distortion": func(dis dataset.Distortion) string {
+		if dis.DissortionsInfo == nil {
+			return "unknown"
+		}
+		return dis.DissortionsInfo.Combination()
+	},
+	"count": func(dis dataset.Distortion) string {
+		switch n := len(dis.DissortionsInfo); {
+		case dis.DissortionsInfo == nil:
+			return "unknown"
+		case n == 1:
+			return "1 distortion"
+		default:
+			return fmt.Sprintf("%d distortions", n)
+		}
+	},
+}
+
+// Returns sorted names of plot colorings.
+func plotColoringNames() []string {
+	res := []string{}
+	for name := range plotColorings {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Options of written metric plots.
+type plotOptions struct {
+	// Directory for plots, empty for no plots.
+	dir string
+	// Name of points coloring from plotColorings.
+	coloring string
+}
+
+// Returns plot options with values set by flags added to fs.
+func addPlotFlags(fs *flag.FlagSet) *plotOptions {
+	o := &plotOptions{coloring: "distortion"}
+	fs.StringVar(&o.dir, "plots", "", "directory for SVG scatter plots of MOS against every metric with fitted logistic5 curve, empty for no plots")
+	o.addColoringFlag(fs)
+	return o
+}
+
+// Adds flag for plot points coloring to fs, setting value to o.
+func (o *plotOptions) addColoringFlag(fs *flag.FlagSet) {
+	fs.Func("plot-color", "color plot points by: "+strings.Join(plotColoringNames(), ", ")+" (default \"distortion\")", func(v string) error {
+		if _, ok := plotColorings[v]; !ok {
+			return fmt.Errorf("unknown plot coloring %q, available: %s", v, strings.Join(plotColoringNames(), ", "))
+		}
+		o.coloring = v
+		return nil
+	})
+}
+
+// Returns logistic5 function fitted to map metric values to mos. Pairs containing NaN values are not used for fitting.
+func fitMOS(mos, values []float64) (stats.LogisticFit, error) {
+	x, y, _ := stats.PairwiseComplete(values, mos)
+	return stats.FitLogistic(x, y, stats.Logistic5)
+}
+
+// Returns scatter plot of mos against metric values of every distorted image in dataset ds with logistic5 curve fitted to them.
+// Points are colored by coloring (name from plotColorings). If fitting fails, error is logged and plot has no curve.
+func metricScatter(ds dataset.Dataset, metric string, mos, values []float64, coloring string) *plot.Scatter {
+	xLabel := metric
+	if info, ok := metrics.Lookup(metric); ok {
+		xLabel = fmt.Sprintf("%s (%s)", metric, info.Direction)
+	}
+	s := &plot.Scatter{Title: metric, XLabel: xLabel, YLabel: "MOS"}
+
+	i := 0
+	for _, ref := range ds {
+		for _, dis := range ref.Distorted {
+			s.Points = append(s.Points, plot.Point{X: values[i], Y: mos[i], Group: plotColorings[coloring](dis)})
+			i++
+		}
+	}
+
+	fit, err := fitMOS(mos, values)
+	if err != nil {
+		log.Printf("Could not fit %s to %s values: %v", stats.Logistic5.Name, metric, err)
+		return s
+	}
+	s.Curve = func(x float64) float64 {
+		return fit.Model.Function(fit.Params, x)
+	}
+	s.CurveLabel = fit.Model.Name + " fit"
+	return s
+}
+
+// Writes SVG scatter plot (see metricScatter) of MOS against every metric in rows to directory from options, if set.
+// Data returns MOS and metric values (not oriented) for row. Files are named by metric (e.g. "SSIM.svg").
+func writePlots(ds dataset.Dataset, rows []string, data func(ds dataset.Dataset, row string) (mos, values []float64), options *plotOptions) error {
+	if options.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(options.dir, 0755); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		mos, values := data(ds, row)
+		path := filepath.Join(options.dir, strings.ReplaceAll(row, "/", "_")+".svg")
+		if err := metricScatter(ds, row, mos, values, options.coloring).WriteSVGFile(path); err != nil {
+			return fmt.Errorf("writing plot \"%s\" error: %w", path, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Plots written to \"%s\": %v\n", options.dir, rows)
+	return nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/png"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jezek/goMDID/dataset"
+	"github.com/jezek/goMDID/metrics"
+	"github.com/jezek/goMDID/table"
+	"golang.org/x/image/draw"
+)
+
+// Style sheet of HTML report.
+const reportStyle = `body { font-family: sans-serif; margin: 2em; color: #222; }
+h1, h2, h3 { font-weight: normal; }
+h2 { border-bottom: 1px solid #ccc; margin-top: 2em; }
+table { border-collapse: collapse; margin: 1em 0; }
+caption { text-align: left; font-style: italic; padding-bottom: 0.3em; }
+th, td { border: 1px solid #ddd; padding: 0.2em 0.6em; }
+thead th { background: #f4f4f4; }
+tbody th { text-align: left; }
+td.num { text-align: right; font-family: monospace; }
+figure { display: inline-block; margin: 0.5em; }
+img { display: block; }
+`
+
+// Generates single HTML file report of computed metrics evaluation on dataset.
+func runReport(args []string) error {
+	fs := newFlagSet("report", "")
+	datasetDir := fs.String("dataset", defaultDatasetDir, "dataset directory")
+	datasetFormat := fs.String("format", defaultDatasetFormat, "dataset format: "+strings.Join(dataset.LoaderNames(), ", "))
+	metricsFlag := fs.String("metrics", defaultComputedMetrics, "comma separated list of computed metrics")
+	evaluatorsFlag := fs.String("evaluators", defaultEvaluators, "comma separated list of evaluators")
+	tableOpts := addEvaluationFlags(fs)
+	tableOpts.addSignificanceFlags(fs)
+	tableOpts.groupings = []string{"distortion", "count"}
+	plotOpts := &plotOptions{coloring: "distortion"}
+	plotOpts.addColoringFlag(fs)
+	workers := fs.Int("workers", defaultWorkers, "number of metrics computed in parallel")
+	storeName := fs.String("store", dataset.DefaultStoreName, "file storing computed metrics (relative to dataset directory), values found in it are not recomputed, empty for no store")
+	worst := fs.Int("worst", 10, "number of worst predicted distorted images shown for every metric")
+	thumbSize := fs.Int("thumb", 160, "maximal width and height of images thumbnails in pixels")
+	output := fs.String("o", "report.html", "output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *thumbSize < 1 {
+		return fmt.Errorf("thumbnail size must be positive, got -thumb %d", *thumbSize)
+	}
+	if *worst < 0 {
+		return fmt.Errorf("number of worst predicted images must not be negative, got -worst %d", *worst)
+	}
+
+	evaluatorsList, err := parseEvaluators(*evaluatorsFlag)
+	if err != nil {
+		return err
+	}
+	metricsList, err := parseMetrics(*metricsFlag)
+	if err != nil {
+		return err
+	}
+	ds, err := loadDataset(*datasetFormat, *datasetDir)
+	if err != nil {
+		return err
+	}
+	store := storePath(*datasetDir, *storeName)
+	if err := computeMetrics(ds, metricsList, *workers, store); err != nil {
+		return err
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintln(b, "<!DOCTYPE html>")
+	fmt.Fprintln(b, `<html lang="en"><head><meta charset="utf-8">`)
+	fmt.Fprintf(b, "<title>goMDID report: %s</title>\n", html.EscapeString(*datasetDir))
+	fmt.Fprintf(b, "<style>\n%s</style>\n", reportStyle)
+	fmt.Fprintln(b, "</head><body>")
+	fmt.Fprintf(b, "<h1>goMDID report: %s dataset</h1>\n", html.EscapeString(*datasetFormat))
+
+	if store == "" {
+		store = "none"
+	}
+	writeReportMetadata(b, ds, *datasetDir, *datasetFormat, store, metricsList, evaluatorsList)
+
+	// Evaluation tables are rendered to report as HTML tables, best values are emphasized.
+	tableOpts.renderer, tableOpts.renderOptions, tableOpts.out = table.RendererFunc(table.RenderHTML), table.Options{BoldBest: true}, b
+	data := func(ds dataset.Dataset, cm string) ([]float64, []float64) {
+		return ds.ProvidedMetricsByName("mos"), orient(cm, ds.ComputedMetricsByName(cm))
+	}
+	fmt.Fprintln(b, "<h2>Summary</h2>")
+	fmt.Fprintln(b, "<p>Values of lower-is-better metrics are negated before evaluation, so positive correlation means agreement with MOS.</p>")
+	printTable("Comparing dataset MOS to computed metrics (cm) rankings using different evaluators (ev):", "cm\\ev", metricsList, evaluatorsList, func(row string) ([]float64, []float64) {
+		return data(ds, row)
+	}, tableOpts)
+	for _, g := range tableOpts.groupings {
+		fmt.Fprintf(b, "<h2>Breakdown by %s</h2>\n", html.EscapeString(g))
+		for _, group := range groupings[g](ds) {
+			fmt.Fprintf(b, "<h3>%s (%d distorted images)</h3>\n", html.EscapeString(group.Name), group.Dataset.DistortedCount())
+			printTable(fmt.Sprintf("Group by %s: %s", g, group.Name), "cm\\ev", metricsList, evaluatorsList, func(row string) ([]float64, []float64) {
+				return data(group.Dataset, row)
+			}, tableOpts)
+		}
+	}
+
+	fmt.Fprintln(b, "<h2>Scatter plots</h2>")
+	fmt.Fprintf(b, "<p>MOS against metric values with fitted logistic5 function, points colored by %s.</p>\n", html.EscapeString(plotOpts.coloring))
+	mos := ds.ProvidedMetricsByName("mos")
+	for _, m := range metricsList {
+		fmt.Fprintln(b, "<figure>")
+		if err := metricScatter(ds, m, mos, ds.ComputedMetricsByName(m), plotOpts.coloring).WriteSVG(b); err != nil {
+			return err
+		}
+		fmt.Fprintln(b, "</figure>")
+	}
+
+	fmt.Fprintln(b, "<h2>Worst predicted images</h2>")
+	fmt.Fprintf(b, "<p>Distorted images with the largest absolute difference between MOS and MOS predicted by fitted logistic5 function of metric value (at most %d per metric).</p>\n", *worst)
+	thumbs := &thumbnails{size: *thumbSize, cache: map[string]string{}}
+	for _, m := range metricsList {
+		fmt.Fprintf(b, "<h3>%s</h3>\n", html.EscapeString(m))
+		writeWorstPredicted(b, ds, m, mos, ds.ComputedMetricsByName(m), *worst, thumbs)
+	}
+	fmt.Fprintln(b, "</body></html>")
+
+	if err := os.WriteFile(*output, b.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing report error: %w", err)
+	}
+	log.Printf("Report written to %s", *output)
+	return nil
+}
+
+// Writes run metadata (time, dataset, metrics and their versions, evaluators) to report b.
+func writeReportMetadata(b *bytes.Buffer, ds dataset.Dataset, dir, format, store string, metricsList, evaluatorsList []string) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	fmt.Fprintln(b, "<h2>Run</h2>")
+	fmt.Fprintln(b, "<table><tbody>")
+	for _, item := range [][2]string{
+		{"Generated", time.Now().Format(time.RFC3339)},
+		{"Dataset path", dir},
+		{"Dataset format", format},
+		{"Reference images", fmt.Sprint(len(ds))},
+		{"Distorted images", fmt.Sprint(ds.DistortedCount())},
+		{"Metrics store", store},
+		{"Evaluators", strings.Join(evaluatorsList, ", ")},
+		{"Command", strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " ")},
+	} {
+		fmt.Fprintf(b, "<tr><th>%s</th><td>%s</td></tr>\n", item[0], html.EscapeString(item[1]))
+	}
+	fmt.Fprintln(b, "</tbody></table>")
+
+	t := table.New("Computed metrics:", "metric", table.Column{Name: "direction"}, table.Column{Name: "range"}, table.Column{Name: "images"}, table.Column{Name: "version"}, table.Column{Name: "config"})
+	for _, m := range metricsList {
+		info, _ := metrics.Lookup(m)
+		t.AddRow(m,
+			table.Text(info.Direction.String()),
+			table.Text(fmt.Sprintf("[%g, %g]", info.Min, info.Max)),
+			table.Text(info.Color.String()),
+			table.Text(strconv.Itoa(info.Version)),
+			table.Text(info.ConfigString()),
+		)
+	}
+	if err := table.RenderHTML(b, t, table.Options{}); err != nil {
+		log.Printf("Could not render table: %v", err)
+	}
+}
+
+// Writes table of at most n distorted images, which MOS is worst predicted by logistic5 function fitted to metric values, to report b.
+// Images are shown as thumbnails of reference and distorted image.
+func writeWorstPredicted(b *bytes.Buffer, ds dataset.Dataset, metric string, mos, values []float64, n int, thumbs *thumbnails) {
+	fit, err := fitMOS(mos, values)
+	if err != nil {
+		fmt.Fprintf(b, "<p>Could not fit logistic5 function: %s</p>\n", html.EscapeString(err.Error()))
+		return
+	}
+
+	type prediction struct {
+		ref       dataset.Reference
+		dis       dataset.Distortion
+		mos, x, y float64
+	}
+	predictions := []prediction{}
+	i := 0
+	for _, ref := range ds {
+		for _, dis := range ref.Distorted {
+			p := prediction{ref, dis, mos[i], values[i], fit.Model.Function(fit.Params, values[i])}
+			if !math.IsNaN(p.mos) && !math.IsNaN(p.y) {
+				predictions = append(predictions, p)
+			}
+			i++
+		}
+	}
+	sort.SliceStable(predictions, func(i, j int) bool {
+		return math.Abs(predictions[i].mos-predictions[i].y) > math.Abs(predictions[j].mos-predictions[j].y)
+	})
+	if len(predictions) > n {
+		predictions = predictions[:n]
+	}
+
+	fmt.Fprintln(b, "<table>")
+	fmt.Fprintf(b, "<thead><tr><th>#</th><th>reference</th><th>distorted</th><th>image</th><th>distortions</th><th>MOS</th><th>%s</th><th>predicted MOS</th><th>difference</th></tr></thead>\n", html.EscapeString(metric))
+	fmt.Fprintln(b, "<tbody>")
+	for i, p := range predictions {
+		fmt.Fprintf(b, "<tr><td class=\"num\">%d</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td class=\"num\">%.4f</td><td class=\"num\">%.4f</td><td class=\"num\">%.4f</td><td class=\"num\">%+.4f</td></tr>\n",
+			i+1, thumbs.img(p.ref.Path), thumbs.img(p.dis.Path),
+			html.EscapeString(filepath.Base(p.dis.Path)), html.EscapeString(p.dis.DissortionsInfo.String()),
+			p.mos, p.x, p.y, p.mos-p.y,
+		)
+	}
+	fmt.Fprintln(b, "</tbody></table>")
+}
+
+// Thumbnails of images embedded in report, cached by image path.
+type thumbnails struct {
+	// Maximal thumbnail width and height.
+	size  int
+	cache map[string]string
+}
+
+// Returns HTML img element with thumbnail of image on path embedded as PNG data URI, or error text if image could not be loaded.
+func (t *thumbnails) img(path string) string {
+	if s, ok := t.cache[path]; ok {
+		return s
+	}
+	s, err := thumbnailDataURI(path, t.size)
+	if err != nil {
+		log.Printf("Could not create thumbnail of %s: %v", path, err)
+		s = "(image not loaded)"
+	} else {
+		s = fmt.Sprintf(`<img src="%s" alt="%s">`, s, html.EscapeString(filepath.Base(path)))
+	}
+	t.cache[path] = s
+	return s
+}
+
+// Returns PNG data URI of image on path scaled down (keeping aspect ratio) to fit size×size pixels.
+func thumbnailDataURI(path string, size int) (string, error) {
+	img, err := dataset.ImageFromPath(path)
+	if err != nil {
+		return "", err
+	}
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, (h*size+w-1)/w
+		} else {
+			w, h = (w*size+h-1)/h, size
+		}
+	}
+	thumb := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, bounds, draw.Src, nil)
+
+	b := &bytes.Buffer{}
+	if err := png.Encode(b, thumb); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(b.Bytes()), nil
+}
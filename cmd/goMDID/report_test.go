@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/bmp"
+)
+
+// Writes tiny MDID dataset with 2 reference images and 5 distorted images of each (GN1 ... GN5) into dir.
+// Distorted images have pseudo-random noise of amplitude growing with level and MOS falls with level.
+func writeReportTestDataset(t *testing.T, dir string) {
+	t.Helper()
+	for _, d := range []string{"reference_images", "distortion_images"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeBMP := func(path string, img image.Image) {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := bmp.Encode(f, img); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mos := []string{}
+	for r := 1; r <= 2; r++ {
+		ref := image.NewGray(image.Rect(0, 0, 24, 24))
+		for y := 0; y < 24; y++ {
+			for x := 0; x < 24; x++ {
+				ref.SetGray(x, y, color.Gray{uint8(60 + 5*x + r*3*y)})
+			}
+		}
+		writeBMP(filepath.Join(dir, "reference_images", fmt.Sprintf("img%02d.bmp", r)), ref)
+
+		for level := 1; level <= 5; level++ {
+			dis := image.NewGray(ref.Bounds())
+			for i := range ref.Pix {
+				noise := (i*7919+level*104729+r*31)%(2*level*8+1) - level*8
+				dis.Pix[i] = uint8(int(ref.Pix[i]) + noise)
+			}
+			writeBMP(filepath.Join(dir, "distortion_images", fmt.Sprintf("img%02d_%d_GN%d.bmp", r, level, level)), dis)
+			mos = append(mos, fmt.Sprint(9-1.5*float64(level)+0.3*float64(r)))
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mos.txt"), []byte(strings.Join(mos, "\r\n")+"\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	dir := t.TempDir()
+	writeReportTestDataset(t, dir)
+	out := filepath.Join(t.TempDir(), "report.html")
+
+	args := []string{"-dataset", dir, "-metrics", "PSNR,SSIM", "-evaluators", "SROCC,PLCC", "-store", "", "-workers", "2", "-worst", "3", "-thumb", "8", "-o", out}
+	if err := runReport(args); err != nil {
+		t.Fatalf("runReport error: %v", err)
+	}
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(content)
+
+	for _, want := range []string{
+		"<tr><th>Dataset format</th><td>MDID</td></tr>",
+		"<tr><th>Reference images</th><td>2</td></tr>",
+		"<tr><th>Distorted images</th><td>10</td></tr>",
+		"<tr><th>Metrics store</th><td>none</td></tr>",
+		"<tr><th>Evaluators</th><td>SROCC, PLCC</td></tr>",
+		"<h3>GN (10 distorted images)</h3>",
+		"<h3>1 distortion (10 distorted images)</h3>",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if n := strings.Count(report, "<svg"); n != 2 {
+		t.Errorf("report contains %d SVG plots, want 2", n)
+	}
+
+	// Worst predicted section has table with 3 rows for every metric, each with reference and distorted thumbnail.
+	worst := report[strings.Index(report, "<h2>Worst predicted images</h2>"):]
+	for _, m := range []string{"PSNR", "SSIM"} {
+		start := strings.Index(worst, "<h3>"+m+"</h3>")
+		if start < 0 {
+			t.Errorf("no worst predicted images of %s", m)
+			continue
+		}
+		section := worst[start:]
+		section = section[:strings.Index(section, "</table>")]
+		if n := strings.Count(section, "<tr><td class=\"num\">"); n != 3 {
+			t.Errorf("%s worst predicted table has %d rows, want 3", m, n)
+		}
+		if n := strings.Count(section, `<img src="data:image/png;base64,`); n != 6 {
+			t.Errorf("%s worst predicted table has %d thumbnails, want 6", m, n)
+		}
+		if !strings.Contains(section, "GN") {
+			t.Errorf("%s worst predicted table has no distortions", m)
+		}
+	}
+}
+
+func TestRunReportInvalidFlags(t *testing.T) {
+	dir := t.TempDir()
+	writeReportTestDataset(t, dir)
+	for _, args := range [][]string{
+		{"-thumb", "0"},
+		{"-thumb", "-5"},
+		{"-worst", "-1"},
+	} {
+		out := filepath.Join(t.TempDir(), "report.html")
+		if err := runReport(append(args, "-dataset", dir, "-metrics", "PSNR", "-store", "", "-o", out)); err == nil {
+			t.Errorf("runReport %v returned nil error", args)
+		}
+		if _, err := os.Stat(out); err == nil {
+			t.Errorf("runReport %v wrote report", args)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package dataset
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CSIQ distortion types (dst_type column values) with type codes used in distorted image names and corresponding MDID distortion kinds.
+var csiqTypes = map[string]struct {
+	code string
+	kind DistortionKind
+	ok   bool // false if there is no corresponding MDID distortion kind
+}{
+	"noise":     {"awgn", GaussianNoise, true},
+	"awgn":      {"awgn", GaussianNoise, true},
+	"blur":      {"blur", GaussianBlur, true},
+	"contrast":  {"contrast", ContrastChange, true},
+	"jpeg":      {"jpeg", JPEG, true},
+	"jpeg 2000": {"jpeg2000", JPEG2000, true},
+	"jpeg2000":  {"jpeg2000", JPEG2000, true},
+	"f noise":   {"fnoise", 0, false},
+	"fnoise":    {"fnoise", 0, false},
+}
+
+// LoadCSIQ loads CSIQ dataset from directory path.
+// Directory contains src_imgs, dst_imgs (with distortion type subdirectories) and dmos.csv, a CSV export of "all_by_image" sheet of csiq.DMOS.xlsx with columns image, dst_type, dst_lev, dmos_std and dmos.
+// Distorted images are named <image>.<type code>.<level>.png (e.g. 1600.AWGN.1.png), names are matched case insensitively.
+func LoadCSIQ(path string) (Dataset, error) {
+	log.Printf("LoadCSIQ(%v)", path)
+
+	refs, err := filesIndex(filepath.Join(path, "src_imgs"))
+	if err != nil {
+		return nil, fmt.Errorf("reading reference images direcory error: %w", err)
+	}
+	distorted, err := filesIndex(filepath.Join(path, "dst_imgs"))
+	if err != nil {
+		return nil, fmt.Errorf("reading distorted images direcory error: %w", err)
+	}
+	records, err := readCSVRecords(filepath.Join(path, "dmos.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("reading dmos.csv error: %w", err)
+	}
+
+	b := newDatasetBuilder()
+	for _, rec := range records {
+		image, typ, lev := rec["image"], strings.ToLower(rec["dst_type"]), rec["dst_lev"]
+		t, ok := csiqTypes[typ]
+		if !ok {
+			log.Printf("\tunknown distortion type %q", rec["dst_type"])
+			continue
+		}
+		disPath, ok := distorted[strings.ToLower(image+"."+t.code+"."+lev+".png")]
+		if !ok {
+			log.Printf("\tno distorted image for %s %s %s", image, typ, lev)
+			continue
+		}
+		refPath, ok := refs[strings.ToLower(image+".png")]
+		if !ok {
+			log.Printf("\tno reference image %s", image)
+			continue
+		}
+
+		info := DistortionsInfo(nil)
+		if level, err := strconv.Atoi(lev); err == nil && t.ok {
+			info = DistortionsInfo{{t.kind, level}}
+		}
+		b.add(refPath, disPath, info, dmosMetrics(parseFloatOrNaN(rec["dmos"]), parseFloatOrNaN(rec["dmos_std"])))
+	}
+	return b.dataset, nil
+}
@@ -0,0 +1,61 @@
+package dataset
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// KADID-10k distortion types (numbers in distorted image names), which correspond to MDID distortion kinds.
+var kadidDistortionKinds = map[int]DistortionKind{
+	1:  GaussianBlur,
+	9:  JPEG2000,
+	10: JPEG,
+	11: GaussianNoise, // white noise
+	25: ContrastChange,
+}
+
+// LoadKADID loads KADID-10k dataset from directory path.
+// Directory contains images (reference and distorted) and dmos.csv with columns dist_img, ref_img, dmos and var (variance of subjective scores).
+// Distorted images are named I<reference>_<type>_<level> (e.g. I01_01_03.png). KADID-10k DMOS values are higher for better quality, so they are stored as "mos" directly.
+func LoadKADID(path string) (Dataset, error) {
+	log.Printf("LoadKADID(%v)", path)
+
+	images, err := filesIndex(filepath.Join(path, "images"))
+	if err != nil {
+		return nil, fmt.Errorf("reading images direcory error: %w", err)
+	}
+	records, err := readCSVRecords(filepath.Join(path, "dmos.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("reading dmos.csv error: %w", err)
+	}
+
+	b := newDatasetBuilder()
+	for _, rec := range records {
+		disPath, ok := images[strings.ToLower(rec["dist_img"])]
+		if !ok {
+			log.Printf("\tno distorted image %s", rec["dist_img"])
+			continue
+		}
+		refPath, ok := images[strings.ToLower(rec["ref_img"])]
+		if !ok {
+			log.Printf("\tno reference image %s", rec["ref_img"])
+			continue
+		}
+
+		pm := Metrics{"mos": parseFloatOrNaN(rec["dmos"])}
+		if v, ok := rec["var"]; ok {
+			pm["mos_std"] = math.Sqrt(parseFloatOrNaN(v))
+		}
+		name := rec["dist_img"]
+		parts := strings.Split(strings.TrimSuffix(name, filepath.Ext(name)), "_")
+		info := DistortionsInfo(nil)
+		if len(parts) == 3 {
+			info = numberedDistortionsInfo(kadidDistortionKinds, parts[1], parts[2])
+		}
+		b.add(refPath, disPath, info, pm)
+	}
+	return b.dataset, nil
+}
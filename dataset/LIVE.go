@@ -0,0 +1,113 @@
+package dataset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LIVE Release 2 distortion folders in order of dmos.mat values, with corresponding MDID distortion kinds (nil info for fast fading).
+var liveFolders = []struct {
+	name string
+	info DistortionsInfo
+}{
+	{"jp2k", DistortionsInfo{{JPEG2000, 1}}},
+	{"jpeg", DistortionsInfo{{JPEG, 1}}},
+	{"wn", DistortionsInfo{{GaussianNoise, 1}}},
+	{"gblur", DistortionsInfo{{GaussianBlur, 1}}},
+	{"fastfading", nil},
+}
+
+// LoadLIVE loads LIVE Image Quality Assessment Database Release 2 from directory path.
+// Directory contains refimgs, distortion folders (jp2k, jpeg, wn, gblur, fastfading) with info.txt (lines "<reference> <distorted> <parameter>") and DMOS values in dmos.mat (variables dmos and orgs).
+// If dmos.mat is not present, DMOS values are read from dmos.csv (CSV export with columns dmos and optional orgs, values in dmos.mat order).
+// Distorted images which are in fact originals (orgs equal 1) are skipped. LIVE does not provide distortion levels, so distortion kinds are stored with level 1.
+func LoadLIVE(path string) (Dataset, error) {
+	log.Printf("LoadLIVE(%v)", path)
+
+	dmos, orgs, err := liveDMOS(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b, offset := newDatasetBuilder(), 0
+	for _, folder := range liveFolders {
+		content, err := ioutil.ReadFile(filepath.Join(path, folder.name, "info.txt"))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s info file error: %w", folder.name, err)
+		}
+
+		count := 0
+		for _, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			count++
+			if len(fields) < 2 {
+				log.Printf("\tunexpected %s info line %q", folder.name, line)
+				continue
+			}
+
+			// Values in dmos.mat are ordered by distorted image number in folder (img<number>.bmp).
+			number, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(fields[1], "img"), filepath.Ext(fields[1])))
+			if err != nil || number < 1 {
+				log.Printf("\tcan not parse distorted image number from %s", fields[1])
+				continue
+			}
+			i := offset + number - 1
+			if i >= len(dmos) {
+				log.Printf("\tno dmos value for %s/%s", folder.name, fields[1])
+				continue
+			}
+			if i < len(orgs) && orgs[i] == 1 {
+				continue
+			}
+			b.add(filepath.Join(path, "refimgs", fields[0]), filepath.Join(path, folder.name, fields[1]), folder.info, dmosMetrics(dmos[i], math.NaN()))
+		}
+		offset += count
+	}
+	if offset != len(dmos) {
+		log.Printf("\tnumber of distorted images %d differs from number of dmos values %d", offset, len(dmos))
+	}
+	return b.dataset, nil
+}
+
+// Returns LIVE dmos and orgs values from dmos.mat, or dmos.csv if there is no dmos.mat.
+func liveDMOS(path string) (dmos, orgs []float64, err error) {
+	matPath := filepath.Join(path, "dmos.mat")
+	if _, err := os.Stat(matPath); err == nil {
+		vars, err := ReadMATFile(matPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading dmos.mat error: %w", err)
+		}
+		dmos, ok := vars["dmos"]
+		if !ok {
+			return nil, nil, fmt.Errorf("no dmos variable in %s", matPath)
+		}
+		return dmos, vars["orgs"], nil
+	}
+
+	records, err := readCSVRecords(filepath.Join(path, "dmos.csv"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading dmos.mat or dmos.csv error: %w", err)
+	}
+	for _, rec := range records {
+		v, err := strconv.ParseFloat(rec["dmos"], 64)
+		if err != nil {
+			log.Printf("\tconverting dmos value %s to float64 error: %v", rec["dmos"], err)
+			v = math.NaN()
+		}
+		dmos = append(dmos, v)
+		if o, ok := rec["orgs"]; ok {
+			v, _ := strconv.ParseFloat(o, 64)
+			orgs = append(orgs, v)
+		}
+	}
+	return dmos, orgs, nil
+}
@@ -1,16 +1,26 @@
-package main
+package dataset
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"math"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
+// MDIDDistortionsFile is name of optional file in MDID dataset directory, which lists distortions of distorted images (see LoadMDID).
+const MDIDDistortionsFile = "distortions.txt"
+
+// LoadMDID loads MDID dataset from directory path.
+// Distortions info of distorted images is read from MDIDDistortionsFile, if the directory contains it, otherwise it is parsed from their file names using ParseMDIDName(...).
+// Each line of distortions file holds distorted image file name and its distortions info separated by whitespace (e.g. "img01_1.bmp GB1+JPEG2", see ParseDistortionsInfo).
+// Images without distortions info have unknown (nil) info and a warning is logged, so groupings by distortions put them into "unknown" group.
+// Returns error if distortions info is not known for any distorted image.
 func LoadMDID(path string) (Dataset, error) {
 	log.Printf("LoadMDID(%v)", path)
 
@@ -37,6 +47,11 @@ func LoadMDID(path string) (Dataset, error) {
 		log.Printf("reading distorted images direcory error: %v", err)
 	}
 
+	distortionsList, err := readMDIDDistortions(filepath.Join(path, MDIDDistortionsFile))
+	if err != nil {
+		return nil, err
+	}
+
 	dataset := make(Dataset, 0, len(referenceFiles))
 	refMap := map[string]int{}
 
@@ -62,6 +77,7 @@ func LoadMDID(path string) (Dataset, error) {
 	}
 
 	log.Printf("\tloading distortion images from %s", distortionsDir)
+	unparsed, distortedCount, unparsedExample := 0, 0, ""
 	for _, fi := range distortedFiles {
 		if fi.IsDir() {
 			log.Printf("\tis dir %s", fi.Name())
@@ -85,13 +101,37 @@ func LoadMDID(path string) (Dataset, error) {
 			continue
 		}
 
+		distortedCount++
+		var info DistortionsInfo
+		var err error
+		if distortionsList != nil {
+			var ok bool
+			if info, ok = distortionsList[fi.Name()]; !ok {
+				err = fmt.Errorf("%s not listed in %s", fi.Name(), MDIDDistortionsFile)
+			}
+		} else {
+			info, err = ParseMDIDName(fi.Name())
+		}
+		if err != nil {
+			if unparsed == 0 {
+				unparsedExample = err.Error()
+			}
+			unparsed++
+		}
+
 		distorted := Distortion{
 			Path:            filepath.Join(distortionsDir, fi.Name()),
+			DissortionsInfo: info,
 			ProvidedMetrics: make(Metrics, len(metricsFiles)),
 			ComputedMetrics: make(Metrics),
 		}
 		dataset[refIndex].Distorted = append(dataset[refIndex].Distorted, distorted)
 	}
+	if unparsed > 0 && unparsed == distortedCount {
+		return nil, fmt.Errorf("distortions info of none of %d distortion images is known (e.g. %s), list distortions in %s", unparsed, unparsedExample, MDIDDistortionsFile)
+	} else if unparsed > 0 {
+		log.Printf("WARNING: distortions info of %d of %d distortion images is not known (e.g. %s), their distortions are unknown", unparsed, distortedCount, unparsedExample)
+	}
 	for _, fi := range metricsFiles {
 		if fi.IsDir() {
 			log.Printf("\tis dir %s", fi.Name())
@@ -170,3 +210,31 @@ func LoadMDID(path string) (Dataset, error) {
 	}
 	return dataset, nil
 }
+
+// Returns distortions info by distorted image file name read from MDID distortions file, or nil map if the file does not exist.
+func readMDIDDistortions(path string) (map[string]DistortionsInfo, error) {
+	content, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading distortions file error: %w", err)
+	}
+
+	res := map[string]DistortionsInfo{}
+	for i, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s line %d: want image name and distortions, got %q", path, i+1, strings.TrimSpace(line))
+		}
+		info, err := ParseDistortionsInfo(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, i+1, err)
+		}
+		res[fields[0]] = info
+	}
+	return res, nil
+}
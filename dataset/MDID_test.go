@@ -0,0 +1,105 @@
+package dataset
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Creates empty files in dir, loader does not read images.
+func writeEmptyFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkMDIDDistortions(t *testing.T, ds Dataset, want ...string) {
+	t.Helper()
+	if len(ds) != 1 || len(ds[0].Distorted) != len(want) {
+		t.Fatalf("loaded %d references, want 1 with %d distorted images", len(ds), len(want))
+	}
+	for i, w := range want {
+		if got := ds[0].Distorted[i].DissortionsInfo.String(); got != w {
+			t.Errorf("%s distortions %q, want %q", filepath.Base(ds[0].Distorted[i].Path), got, w)
+		}
+	}
+}
+
+func TestLoadMDIDDistortionsInfo(t *testing.T) {
+	dir := t.TempDir()
+	writeEmptyFiles(t, dir,
+		"reference_images/img01.bmp",
+		"distortion_images/img01_1_GB1.bmp",
+		"distortion_images/img01_2_CC1_JPEG2.bmp",
+		"distortion_images/img01_3.bmp",
+	)
+
+	ds, err := LoadMDID(dir)
+	if err != nil {
+		t.Fatalf("LoadMDID error: %v", err)
+	}
+	checkMDIDDistortions(t, ds, "GB1", "CC1+JPEG2", "unknown")
+}
+
+func TestLoadMDIDNoDistortionsInfo(t *testing.T) {
+	dir := t.TempDir()
+	writeEmptyFiles(t, dir,
+		"reference_images/img01.bmp",
+		"distortion_images/img01_1.bmp",
+		"distortion_images/img01_2.bmp",
+	)
+
+	_, err := LoadMDID(dir)
+	if err == nil || !strings.Contains(err.Error(), MDIDDistortionsFile) {
+		t.Fatalf("LoadMDID error %v, want error mentioning %s", err, MDIDDistortionsFile)
+	}
+}
+
+func TestLoadMDIDDistortionsFile(t *testing.T) {
+	dir := t.TempDir()
+	writeEmptyFiles(t, dir,
+		"reference_images/img01.bmp",
+		"distortion_images/img01_1.bmp",
+		"distortion_images/img01_2.bmp",
+		"distortion_images/img01_3.bmp",
+		"distortion_images/img01_4_GB1.bmp",
+	)
+	list := "img01_1.bmp GB1+JPEG2\r\nimg01_2.bmp none\r\n\r\nimg01_3.bmp\tGN3+CC1+JP2K2\r\n"
+	if err := os.WriteFile(filepath.Join(dir, MDIDDistortionsFile), []byte(list), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := LoadMDID(dir)
+	if err != nil {
+		t.Fatalf("LoadMDID error: %v", err)
+	}
+	// Listed distortions take precedence over names, unlisted images are unknown.
+	checkMDIDDistortions(t, ds, "GB1+JPEG2", "none", "GN3+CC1+JP2K2", "unknown")
+}
+
+func TestLoadMDIDInvalidDistortionsFile(t *testing.T) {
+	for name, list := range map[string]string{
+		"missing info": "img01_1.bmp\n",
+		"invalid info": "img01_1.bmp XY1\n",
+		"extra field":  "img01_1.bmp GB1 JPEG1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeEmptyFiles(t, dir, "reference_images/img01.bmp", "distortion_images/img01_1.bmp")
+			if err := os.WriteFile(filepath.Join(dir, MDIDDistortionsFile), []byte(list), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadMDID(dir); err == nil {
+				t.Errorf("LoadMDID with distortions file %q returned nil error", list)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package dataset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// TID distortion types (numbers in distorted image names), which correspond to MDID distortion kinds.
+// Numbering of first 17 types is common for TID2008 and TID2013.
+var tidDistortionKinds = map[int]DistortionKind{
+	1:  GaussianNoise,
+	8:  GaussianBlur,
+	10: JPEG,
+	11: JPEG2000,
+	17: ContrastChange,
+}
+
+// LoadTID loads TID2008 or TID2013 dataset from directory path.
+// Directory contains reference_images, distorted_images, mos_with_names.txt (lines "<mos> <distorted image name>") and optional mos_std.txt (one value per line, in mos_with_names.txt order).
+// Distorted images are named i<reference>_<type>_<level> (e.g. i01_08_3.bmp), types corresponding to MDID distortion kinds are stored in distortions info.
+func LoadTID(path string) (Dataset, error) {
+	log.Printf("LoadTID(%v)", path)
+
+	refs, err := filesIndex(filepath.Join(path, "reference_images"))
+	if err != nil {
+		return nil, fmt.Errorf("reading reference images direcory error: %w", err)
+	}
+	distorted, err := filesIndex(filepath.Join(path, "distorted_images"))
+	if err != nil {
+		return nil, fmt.Errorf("reading distorted images direcory error: %w", err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(path, "mos_with_names.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("reading mos file error: %w", err)
+	}
+	stds := []float64(nil)
+	if stdContent, err := ioutil.ReadFile(filepath.Join(path, "mos_std.txt")); err != nil {
+		log.Printf("\treading mos std file error: %v", err)
+	} else {
+		for _, f := range strings.Fields(string(stdContent)) {
+			stds = append(stds, parseFloatOrNaN(f))
+		}
+	}
+
+	b := newDatasetBuilder()
+	i := -1 // index of mos value
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		i++
+		if len(fields) != 2 {
+			log.Printf("\tunexpected mos line %q", line)
+			continue
+		}
+		name := fields[1]
+		disPath, ok := distorted[strings.ToLower(name)]
+		if !ok {
+			log.Printf("\tno distorted image %s", name)
+			continue
+		}
+		parts := strings.Split(strings.TrimSuffix(name, filepath.Ext(name)), "_")
+		if len(parts) != 3 {
+			log.Printf("\tcan not parse distorted image name %s", name)
+			continue
+		}
+		refPath, ok := refs[strings.ToLower(parts[0]+filepath.Ext(name))]
+		if !ok {
+			log.Printf("\tno reference image for %s", name)
+			continue
+		}
+
+		pm := Metrics{"mos": parseFloatOrNaN(fields[0])}
+		if i < len(stds) {
+			pm["mos_std"] = stds[i]
+		}
+		b.add(refPath, disPath, numberedDistortionsInfo(tidDistortionKinds, parts[1], parts[2]), pm)
+	}
+	return b.dataset, nil
+}
@@ -0,0 +1,140 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jezek/goMDID/metrics"
+)
+
+// Job error for jobs which images could not be loaded. Load errors are logged per image.
+var errImageNotLoaded = errors.New("image not loaded")
+
+// Decodes images of jobs, replaceable in tests.
+var decodeImage = ImageFromPath
+
+// Lazily decoded image shared by more jobs. Image is released when all jobs using it are done.
+type sharedImage struct {
+	path    string
+	once    sync.Once
+	img     image.Image
+	err     error
+	pending int32
+}
+
+func (s *sharedImage) get() (image.Image, error) {
+	s.once.Do(func() {
+		s.img, s.err = decodeImage(s.path)
+	})
+	return s.img, s.err
+}
+
+func (s *sharedImage) done() {
+	if atomic.AddInt32(&s.pending, -1) == 0 {
+		s.img = nil
+	}
+}
+
+// ComputeMetrics computes metrics from list for every distorted image against its reference image and stores results in distortions ComputedMetrics.
+// Metrics are computed by metrics from ms (name => metric) in parallel using workers goroutines (at least 1), every image is decoded only once.
+// If progress is not nil, it is called after every computed metric with number of done and total jobs.
+// Distortions which images could not be loaded or metrics could not be computed are logged and skipped. Results do not depend on jobs scheduling.
+func (d Dataset) ComputeMetrics(ms map[string]metrics.Metric, list []string, workers int, progress func(done, total int)) error {
+	return d.computeMetrics(ms, list, workers, progress, nil)
+}
+
+// Computes metrics as ComputeMetrics(...), but if need is not nil, only metrics for which need returns true are computed.
+func (d Dataset) computeMetrics(ms map[string]metrics.Metric, list []string, workers int, progress func(done, total int), need func(ri, di int, metric string) bool) error {
+	for _, m := range list {
+		if _, ok := ms[m]; !ok {
+			return fmt.Errorf("no function for metric %s", m)
+		}
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		ref, dis *sharedImage
+		ri, di   int
+		metric   string
+	}
+	jobs := []job{}
+	for ri, ref := range d {
+		refImg := &sharedImage{path: ref.Path}
+		for di, dis := range ref.Distorted {
+			disImg := &sharedImage{path: dis.Path}
+			for _, m := range list {
+				if need != nil && !need(ri, di, m) {
+					continue
+				}
+				jobs = append(jobs, job{refImg, disImg, ri, di, m})
+				refImg.pending++
+				disImg.pending++
+			}
+		}
+	}
+
+	values, errs := make([]float64, len(jobs)), make([]error, len(jobs))
+	jobIndexes := make(chan int)
+	var wg sync.WaitGroup
+	var doneCount int32
+	var progressMu sync.Mutex
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobIndexes {
+				j := jobs[i]
+				refImg, refErr := j.ref.get()
+				disImg, disErr := j.dis.get()
+				if refErr == nil && disErr == nil {
+					values[i], errs[i] = ms[j.metric].Compute(refImg, disImg)
+				} else {
+					errs[i] = errImageNotLoaded
+				}
+				j.ref.done()
+				j.dis.done()
+
+				if progress != nil {
+					n := int(atomic.AddInt32(&doneCount, 1))
+					progressMu.Lock()
+					progress(n, len(jobs))
+					progressMu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range jobs {
+		jobIndexes <- i
+	}
+	close(jobIndexes)
+	wg.Wait()
+
+	// Store results and log errors in jobs order.
+	logged := map[*sharedImage]bool{}
+	for i, j := range jobs {
+		for _, s := range []*sharedImage{j.ref, j.dis} {
+			if s.err != nil && !logged[s] {
+				log.Printf("Could not load image: %v", s.err)
+				logged[s] = true
+			}
+		}
+		switch errs[i] {
+		case nil:
+			dis := &d[j.ri].Distorted[j.di]
+			if dis.ComputedMetrics == nil {
+				dis.ComputedMetrics = Metrics{}
+			}
+			dis.ComputedMetrics[j.metric] = values[i]
+		case errImageNotLoaded:
+		default:
+			log.Printf("Could not compute metric %s for %s: %v", j.metric, d[j.ri].Distorted[j.di].Path, errs[i])
+		}
+	}
+	return nil
+}
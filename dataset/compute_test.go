@@ -0,0 +1,131 @@
+package dataset
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jezek/goMDID/metrics"
+)
+
+// Writes w x h gray PNG image with all pixels set to v into dir and returns its path.
+func writeTestImage(t *testing.T, dir, name string, w, h int, v uint8) string {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = v
+	}
+	img.SetGray(0, 0, color.Gray{v / 2})
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Returns dataset with one reference and n distorted images written to dir. Distortions have no metrics maps.
+func testDataset(t *testing.T, dir string, n int) Dataset {
+	t.Helper()
+	ref := Reference{Path: writeTestImage(t, dir, "ref.png", 16, 16, 100)}
+	for i := 0; i < n; i++ {
+		ref.Distorted = append(ref.Distorted, Distortion{Path: writeTestImage(t, dir, "dis"+string(rune('a'+i))+".png", 16, 16, uint8(110+10*i))})
+	}
+	return Dataset{ref}
+}
+
+func TestComputeMetricsNilMaps(t *testing.T) {
+	ds := testDataset(t, t.TempDir(), 3)
+	ms := map[string]metrics.Metric{"MSE": metrics.MetricFunc(metrics.MSEErr)}
+	if err := ds.ComputeMetrics(ms, []string{"MSE"}, 2, nil); err != nil {
+		t.Fatalf("ComputeMetrics error: %v", err)
+	}
+	for i, v := range ds.ComputedMetricsByName("MSE") {
+		if v <= 0 {
+			t.Errorf("distortion %d: MSE %v, want positive value", i, v)
+		}
+	}
+}
+
+// Returns dataset with two references written to dir, each with n distorted images.
+func testDataset2(t *testing.T, dir string, n int) Dataset {
+	t.Helper()
+	ds := Dataset{}
+	for r := 0; r < 2; r++ {
+		ref := Reference{Path: writeTestImage(t, dir, fmt.Sprintf("ref%d.png", r), 16, 16, uint8(80+50*r))}
+		for i := 0; i < n; i++ {
+			ref.Distorted = append(ref.Distorted, Distortion{Path: writeTestImage(t, dir, fmt.Sprintf("dis%d_%d.png", r, i), 16, 16, uint8(90+50*r+7*i))})
+		}
+		ds = append(ds, ref)
+	}
+	return ds
+}
+
+var computeTestMetrics = map[string]metrics.Metric{
+	"MSE":   metrics.MetricFunc(metrics.MSEErr),
+	"PSNRg": metrics.MetricFunc(metrics.PSNRErr),
+	"SSIM":  metrics.MetricFunc(metrics.SSIMErr),
+}
+
+func TestComputeMetricsWorkersDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	list := []string{"MSE", "PSNRg", "SSIM"}
+	one, eight := testDataset2(t, dir, 4), testDataset2(t, dir, 4)
+	if err := one.ComputeMetrics(computeTestMetrics, list, 1, nil); err != nil {
+		t.Fatalf("ComputeMetrics with 1 worker error: %v", err)
+	}
+	if err := eight.ComputeMetrics(computeTestMetrics, list, 8, nil); err != nil {
+		t.Fatalf("ComputeMetrics with 8 workers error: %v", err)
+	}
+	for ri := range one {
+		for di := range one[ri].Distorted {
+			a, b := one[ri].Distorted[di].ComputedMetrics, eight[ri].Distorted[di].ComputedMetrics
+			if len(a) != len(list) || !reflect.DeepEqual(a, b) {
+				t.Errorf("reference %d distortion %d: 1 worker computed %v, 8 workers %v", ri, di, a, b)
+			}
+		}
+	}
+}
+
+func TestComputeMetricsDecodesOnce(t *testing.T) {
+	ds := testDataset2(t, t.TempDir(), 4)
+	var mu sync.Mutex
+	decoded := map[string]int{}
+	defer func(f func(string) (image.Image, error)) { decodeImage = f }(decodeImage)
+	decodeImage = func(path string) (image.Image, error) {
+		mu.Lock()
+		decoded[path]++
+		mu.Unlock()
+		return ImageFromPath(path)
+	}
+
+	if err := ds.ComputeMetrics(computeTestMetrics, []string{"MSE", "PSNRg", "SSIM"}, 8, nil); err != nil {
+		t.Fatalf("ComputeMetrics error: %v", err)
+	}
+	paths := 0
+	for _, ref := range ds {
+		if n := decoded[ref.Path]; n != 1 {
+			t.Errorf("reference %s decoded %d times, want 1", ref.Path, n)
+		}
+		paths++
+		for _, dis := range ref.Distorted {
+			if n := decoded[dis.Path]; n != 1 {
+				t.Errorf("distortion %s decoded %d times, want 1", dis.Path, n)
+			}
+			paths++
+		}
+	}
+	if len(decoded) != paths {
+		t.Errorf("decoded %d paths, want %d", len(decoded), paths)
+	}
+}
@@ -1,10 +1,15 @@
-package main
+// Package dataset implements loading of image quality assessment datasets (reference images, distorted images, subjective scores and provided metrics).
+package dataset
 
 import (
 	"fmt"
+	"image"
 	"math"
+	"os"
 	"path/filepath"
 	"strings"
+
+	_ "golang.org/x/image/bmp"
 )
 
 // Dataset type holds more reference images for metrics.
@@ -46,6 +51,18 @@ func (d Dataset) ComputedMetricsByName(metricsName string) []float64 {
 	return res
 }
 
+// DistortionByName returns distortion with image file name (without directory) equal to name and its reference.
+func (d Dataset) DistortionByName(name string) (Reference, Distortion, bool) {
+	for _, ref := range d {
+		for _, dis := range ref.Distorted {
+			if filepath.Base(dis.Path) == name {
+				return ref, dis, true
+			}
+		}
+	}
+	return Reference{}, Distortion{}, false
+}
+
 // Reference type holds path to reference image and more distorted images with their metrics.
 type Reference struct {
 	Path      string
@@ -64,13 +81,13 @@ func (r Reference) String() string {
 // Distortion holds a path to distorted image, its dissortion info and metrics against it's parent reference image.
 type Distortion struct {
 	Path            string
-	DissortionsInfo string
+	DissortionsInfo DistortionsInfo
 	ProvidedMetrics Metrics
 	ComputedMetrics Metrics
 }
 
 func (d Distortion) String() string {
-	lines := []string{fmt.Sprintf("%v", filepath.Base(d.Path))}
+	lines := []string{fmt.Sprintf("%v (%v)", filepath.Base(d.Path), d.DissortionsInfo)}
 
 	pmlines := []string(nil)
 	if len(d.ProvidedMetrics) > 0 {
@@ -85,3 +102,19 @@ func (d Distortion) String() string {
 
 // Metrics (plural) is an map containing name => value pairs.
 type Metrics map[string]float64
+
+// ImageFromPath returns decoded image from file on filepath.
+func ImageFromPath(filepath string) (image.Image, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
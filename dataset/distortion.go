@@ -0,0 +1,204 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DistortionKind is a kind of distortion applied to reference image.
+type DistortionKind int
+
+// Distortion kinds of MDID dataset.
+const (
+	GaussianNoise DistortionKind = iota
+	GaussianBlur
+	ContrastChange
+	JPEG
+	JPEG2000
+)
+
+// DistortionKinds lists all distortion kinds in order.
+var DistortionKinds = []DistortionKind{GaussianNoise, GaussianBlur, ContrastChange, JPEG, JPEG2000}
+
+// Short codes of distortion kinds, as used in file names.
+var distortionCodes = map[DistortionKind]string{
+	GaussianNoise:  "GN",
+	GaussianBlur:   "GB",
+	ContrastChange: "CC",
+	JPEG:           "JPEG",
+	JPEG2000:       "JP2K",
+}
+
+// Other recognized codes of distortion kinds in file names.
+var distortionCodeAliases = map[string]DistortionKind{
+	"JPG":      JPEG,
+	"J2K":      JPEG2000,
+	"JPEG2000": JPEG2000,
+}
+
+// String returns short code of distortion kind (e.g. "GB" for GaussianBlur).
+func (k DistortionKind) String() string {
+	if c, ok := distortionCodes[k]; ok {
+		return c
+	}
+	return fmt.Sprintf("DistortionKind(%d)", int(k))
+}
+
+// DistortionLevel is a distortion kind applied at level. Higher level means stronger distortion.
+type DistortionLevel struct {
+	Kind  DistortionKind
+	Level int
+}
+
+func (dl DistortionLevel) String() string {
+	return dl.Kind.String() + strconv.Itoa(dl.Level)
+}
+
+// DistortionsInfo lists distortions applied to reference image, sorted by kind. Distortions with level 0 (not applied) are not listed.
+// Nil DistortionsInfo means distortions are unknown, empty non nil DistortionsInfo means no distortions were applied.
+type DistortionsInfo []DistortionLevel
+
+// String returns distortions with levels joined by "+" (e.g. "GB2+JPEG1"), "none" if there are no distortions or "unknown" for nil info.
+func (di DistortionsInfo) String() string {
+	if di == nil {
+		return "unknown"
+	}
+	if len(di) == 0 {
+		return "none"
+	}
+	strs := make([]string, len(di))
+	for i, dl := range di {
+		strs[i] = dl.String()
+	}
+	return strings.Join(strs, "+")
+}
+
+// Combination returns distortion kinds without levels joined by "+" (e.g. "GB+JPEG"), "none" if there are no distortions or "unknown" for nil info.
+// Distortions with the same combination differ only in levels.
+func (di DistortionsInfo) Combination() string {
+	if di == nil {
+		return "unknown"
+	}
+	if len(di) == 0 {
+		return "none"
+	}
+	strs := make([]string, len(di))
+	for i, dl := range di {
+		strs[i] = dl.Kind.String()
+	}
+	return strings.Join(strs, "+")
+}
+
+// Level returns level of distortion kind k, 0 if not applied.
+func (di DistortionsInfo) Level(k DistortionKind) int {
+	for _, dl := range di {
+		if dl.Kind == k {
+			return dl.Level
+		}
+	}
+	return 0
+}
+
+// Has returns true if distortion kind k was applied.
+func (di DistortionsInfo) Has(k DistortionKind) bool {
+	return di.Level(k) > 0
+}
+
+// ErrNoDistortionCodes is returned by ParseMDIDName(...) for file names, which do not encode distortions.
+var ErrNoDistortionCodes = errors.New("no distortion codes in file name")
+
+// ParseMDIDName returns distortions encoded in MDID distorted image file name (directory and extension are ignored).
+// Name consists of tokens separated by underscores. It starts with reference image name (e.g. "img01"), optionally followed by number of distorted image,
+// and distortion codes with levels follow, e.g. "img01_3_CC1_JPEG2.bmp" (third distorted image of img01) or "img01_GB2_CC0_JPEG1.bmp".
+// Codes are GN (gaussian noise), GB (gaussian blur), CC (contrast change), JPEG and JP2K (JPEG2000) with aliases JPG, J2K and JPEG2000, case insensitive.
+// Level 0 means distortion was not applied, distortion kinds without code were not applied too.
+// Names consisting only of reference name and numbers (e.g. "img01_1_1.bmp") do not encode distortions, ErrNoDistortionCodes is returned for them.
+// Images named only by reference and number need distortions listed in MDIDDistortionsFile instead (see LoadMDID).
+// Returns error also if some distortion kind is encoded more times.
+func ParseMDIDName(name string) (DistortionsInfo, error) {
+	name = filepath.Base(name)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	levels, found := map[DistortionKind]int{}, false
+	for _, token := range strings.Split(name, "_") {
+		kind, level, ok := parseDistortionToken(token)
+		if !ok {
+			continue
+		}
+		if _, ok := levels[kind]; ok {
+			return nil, fmt.Errorf("distortion %v encoded more times in %q", kind, name)
+		}
+		levels[kind], found = level, true
+	}
+	if !found {
+		return nil, fmt.Errorf("%q: %w", name, ErrNoDistortionCodes)
+	}
+
+	res := DistortionsInfo{}
+	for kind, level := range levels {
+		if level > 0 {
+			res = append(res, DistortionLevel{kind, level})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Kind < res[j].Kind
+	})
+	return res, nil
+}
+
+// Returns distortion kind and level encoded in token (e.g. "GB2"). Longest matching code is used, so "JPEG20001" is JPEG2000 at level 1.
+func parseDistortionToken(token string) (DistortionKind, int, bool) {
+	token = strings.ToUpper(token)
+	kind, code := DistortionKind(0), ""
+	for k, c := range distortionCodes {
+		if strings.HasPrefix(token, c) && len(c) > len(code) {
+			kind, code = k, c
+		}
+	}
+	for c, k := range distortionCodeAliases {
+		if strings.HasPrefix(token, c) && len(c) > len(code) {
+			kind, code = k, c
+		}
+	}
+	if code == "" {
+		return 0, 0, false
+	}
+	level, err := strconv.Atoi(token[len(code):])
+	if err != nil || level < 0 {
+		return 0, 0, false
+	}
+	return kind, level, true
+}
+
+// ParseDistortionsInfo returns distortions info from its string representation (see DistortionsInfo.String), e.g. "GB2+JPEG1".
+// Returns nil info for "unknown" or empty string.
+func ParseDistortionsInfo(s string) (DistortionsInfo, error) {
+	switch s {
+	case "", "unknown":
+		return nil, nil
+	case "none":
+		return DistortionsInfo{}, nil
+	}
+
+	res := DistortionsInfo{}
+	for _, token := range strings.Split(s, "+") {
+		kind, level, ok := parseDistortionToken(token)
+		if !ok {
+			return nil, fmt.Errorf("invalid distortion %q in %q", token, s)
+		}
+		if res.Level(kind) > 0 {
+			return nil, fmt.Errorf("distortion %v encoded more times in %q", kind, s)
+		}
+		if level > 0 {
+			res = append(res, DistortionLevel{kind, level})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Kind < res[j].Kind
+	})
+	return res, nil
+}
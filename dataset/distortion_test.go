@@ -0,0 +1,101 @@
+package dataset
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseMDIDName(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		want DistortionsInfo
+		err  bool
+	}{
+		{"img01_3_CC1_JPEG2.bmp", DistortionsInfo{{ContrastChange, 1}, {JPEG, 2}}, false},
+		{"distortion_images/img20_80_GN1_GB1_JPEG1.bmp", DistortionsInfo{{GaussianNoise, 1}, {GaussianBlur, 1}, {JPEG, 1}}, false},
+		{"img01_GB2_CC0_JPEG1.bmp", DistortionsInfo{{GaussianBlur, 2}, {JPEG, 1}}, false},
+		{"img01_4_jp2k3_gb2", DistortionsInfo{{GaussianBlur, 2}, {JPEG2000, 3}}, false},
+		{"img01_1_JPG1_J2K2.bmp", DistortionsInfo{{JPEG, 1}, {JPEG2000, 2}}, false},
+		{"img01_1_JPEG20001.bmp", DistortionsInfo{{JPEG2000, 1}}, false},
+		{"img01_2_GN0_CC0.bmp", DistortionsInfo{}, false},
+		{"img01_1_1.bmp", nil, true},
+		{"img01.bmp", nil, true},
+		{"img01_1_GB1_GB2.bmp", nil, true},
+	} {
+		got, err := ParseMDIDName(c.name)
+		if (err != nil) != c.err {
+			t.Errorf("ParseMDIDName(%q) error %v, want error %v", c.name, err, c.err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseMDIDName(%q) = %#v, want %#v", c.name, got, c.want)
+		}
+	}
+
+	if _, err := ParseMDIDName("img01_1_1.bmp"); !errors.Is(err, ErrNoDistortionCodes) {
+		t.Errorf("ParseMDIDName of numbered name error %v, want %v", err, ErrNoDistortionCodes)
+	}
+}
+
+func TestParseDistortionsInfo(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want DistortionsInfo
+	}{
+		{"GB2+JPEG1", DistortionsInfo{{GaussianBlur, 2}, {JPEG, 1}}},
+		{"GN1+GB3+CC2+JPEG1+JP2K2", DistortionsInfo{{GaussianNoise, 1}, {GaussianBlur, 3}, {ContrastChange, 2}, {JPEG, 1}, {JPEG2000, 2}}},
+		{"JP2K1", DistortionsInfo{{JPEG2000, 1}}},
+		{"none", DistortionsInfo{}},
+		{"unknown", nil},
+	} {
+		got, err := ParseDistortionsInfo(c.s)
+		if err != nil {
+			t.Errorf("ParseDistortionsInfo(%q) error: %v", c.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseDistortionsInfo(%q) = %#v, want %#v", c.s, got, c.want)
+		}
+		// String representation is parsed back to the same info.
+		if got.String() != c.s {
+			t.Errorf("ParseDistortionsInfo(%q).String() = %q", c.s, got.String())
+		}
+	}
+
+	// Other forms are normalized.
+	for s, want := range map[string]string{
+		"jpeg1+gb2": "GB2+JPEG1",
+		"GB0+CC1":   "CC1",
+		"GB0":       "none",
+		"":          "unknown",
+	} {
+		got, err := ParseDistortionsInfo(s)
+		if err != nil {
+			t.Errorf("ParseDistortionsInfo(%q) error: %v", s, err)
+			continue
+		}
+		if got.String() != want {
+			t.Errorf("ParseDistortionsInfo(%q).String() = %q, want %q", s, got.String(), want)
+		}
+	}
+
+	for _, s := range []string{"GB", "XY1", "GB1+GB2", "GB-1"} {
+		if _, err := ParseDistortionsInfo(s); err == nil {
+			t.Errorf("ParseDistortionsInfo(%q): no error", s)
+		}
+	}
+}
+
+func TestDistortionsInfoCombination(t *testing.T) {
+	di := DistortionsInfo{{GaussianBlur, 2}, {JPEG, 1}}
+	if c := di.Combination(); c != "GB+JPEG" {
+		t.Errorf("Combination() = %q, want GB+JPEG", c)
+	}
+	if !di.Has(JPEG) || di.Has(GaussianNoise) || di.Level(GaussianBlur) != 2 {
+		t.Errorf("Has/Level of %v do not match", di)
+	}
+	if c := DistortionsInfo(nil).Combination(); c != "unknown" {
+		t.Errorf("nil Combination() = %q, want unknown", c)
+	}
+}
@@ -0,0 +1,197 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Column name prefixes of exported provided and computed metrics and distortion levels.
+const (
+	exportProvidedPrefix = "provided:"
+	exportComputedPrefix = "computed:"
+	exportLevelPrefix    = "level:"
+)
+
+// Export writes dataset to w as CSV with header and one row per distortion.
+// Columns are reference and distorted image names, their paths, distortions info (see DistortionsInfo.String), levels of every distortion kind (empty if info is unknown), mos, mos_std and all provided and computed metrics (prefixed by "provided:" and "computed:").
+// Missing values are empty, NaN values are written as "NaN".
+func (d Dataset) Export(w io.Writer) error {
+	provided, computed := map[string]bool{}, map[string]bool{}
+	for _, ref := range d {
+		for _, dis := range ref.Distorted {
+			for m := range dis.ProvidedMetrics {
+				if m != "mos" && m != "mos_std" {
+					provided[m] = true
+				}
+			}
+			for m := range dis.ComputedMetrics {
+				computed[m] = true
+			}
+		}
+	}
+	providedList, computedList := sortedKeys(provided), sortedKeys(computed)
+
+	header := []string{"reference", "distorted", "reference_path", "distorted_path", "distortions"}
+	for _, k := range DistortionKinds {
+		header = append(header, exportLevelPrefix+k.String())
+	}
+	header = append(header, "mos", "mos_std")
+	for _, m := range providedList {
+		header = append(header, exportProvidedPrefix+m)
+	}
+	for _, m := range computedList {
+		header = append(header, exportComputedPrefix+m)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, ref := range d {
+		for _, dis := range ref.Distorted {
+			row := []string{filepath.Base(ref.Path), filepath.Base(dis.Path), ref.Path, dis.Path, dis.DissortionsInfo.String()}
+			for _, k := range DistortionKinds {
+				level := ""
+				if dis.DissortionsInfo != nil {
+					level = strconv.Itoa(dis.DissortionsInfo.Level(k))
+				}
+				row = append(row, level)
+			}
+			row = append(row, exportValue(dis.ProvidedMetrics, "mos"), exportValue(dis.ProvidedMetrics, "mos_std"))
+			for _, m := range providedList {
+				row = append(row, exportValue(dis.ProvidedMetrics, m))
+			}
+			for _, m := range computedList {
+				row = append(row, exportValue(dis.ComputedMetrics, m))
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Returns sorted keys of set.
+func sortedKeys(set map[string]bool) []string {
+	res := make([]string, 0, len(set))
+	for k := range set {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Returns formatted value of metric m, empty string if there is no such metric.
+func exportValue(metrics Metrics, m string) string {
+	v, ok := metrics[m]
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// ExportFile writes dataset to file on path using Export(...).
+func (d Dataset) ExportFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := d.Export(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Import reads dataset written by Export(...). Images are not needed, paths are taken as they were exported.
+// Distortions info is parsed using ParseDistortionsInfo(...), level columns and unknown columns are ignored.
+func Import(r io.Reader) (Dataset, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header error: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[h] = i
+	}
+	for _, h := range []string{"reference", "distorted"} {
+		if _, ok := col[h]; !ok {
+			return nil, fmt.Errorf("no %s column", h)
+		}
+	}
+	get := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	b := newDatasetBuilder()
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading line %d error: %w", line, err)
+		}
+
+		refPath, disPath := get(row, "reference_path"), get(row, "distorted_path")
+		if refPath == "" {
+			refPath = get(row, "reference")
+		}
+		if disPath == "" {
+			disPath = get(row, "distorted")
+		}
+		info, err := ParseDistortionsInfo(get(row, "distortions"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		provided, computed := Metrics{}, Metrics{}
+		for i, h := range header {
+			if i >= len(row) || row[i] == "" {
+				continue
+			}
+			var metrics Metrics
+			name := h
+			switch {
+			case h == "mos" || h == "mos_std":
+				metrics = provided
+			case strings.HasPrefix(h, exportProvidedPrefix):
+				metrics, name = provided, strings.TrimPrefix(h, exportProvidedPrefix)
+			case strings.HasPrefix(h, exportComputedPrefix):
+				metrics, name = computed, strings.TrimPrefix(h, exportComputedPrefix)
+			default:
+				continue
+			}
+			v, err := strconv.ParseFloat(row[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: converting %s value %q error: %w", line, h, row[i], err)
+			}
+			metrics[name] = v
+		}
+
+		b.addDistortion(refPath, Distortion{Path: disPath, DissortionsInfo: info, ProvidedMetrics: provided, ComputedMetrics: computed})
+	}
+	return b.dataset, nil
+}
+
+// ImportFile reads dataset from file on path written by Export(...) (or ExportFile(...)).
+func ImportFile(path string) (Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Import(f)
+}
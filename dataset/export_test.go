@@ -0,0 +1,117 @@
+package dataset
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Returns true if metrics a, b have the same names and values, NaN values are equal.
+func equalMetrics(a, b Metrics) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for m, va := range a {
+		vb, ok := b[m]
+		if !ok || !(va == vb || math.IsNaN(va) && math.IsNaN(vb)) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExportImport(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	ds := Dataset{
+		{Path: "ref/img01.bmp", Distorted: []Distortion{
+			{
+				Path:            "dis/img01_1_GB1.bmp",
+				DissortionsInfo: DistortionsInfo{{GaussianBlur, 1}},
+				ProvidedMetrics: Metrics{"mos": 5.25, "mos_std": 0.5, "psnr": 31.5},
+				ComputedMetrics: Metrics{"SSIM": 0.9, "PSNR": inf},
+			},
+			{
+				Path:            "dis/img01_2_CC1_JPEG2.bmp",
+				DissortionsInfo: DistortionsInfo{{ContrastChange, 1}, {JPEG, 2}},
+				ProvidedMetrics: Metrics{"mos": nan, "mos_std": 1e-17},
+				ComputedMetrics: Metrics{"SSIM": nan, "PSNR": -inf},
+			},
+		}},
+		{Path: "ref/with, comma \"quoted\".bmp", Distorted: []Distortion{
+			{
+				Path:            "dis/unknown.bmp",
+				ProvidedMetrics: Metrics{"mos": 1, "psnr": -0.1},
+				ComputedMetrics: Metrics{"SSIM": 1.0 / 3},
+			},
+			{
+				Path:            "dis/none.bmp",
+				DissortionsInfo: DistortionsInfo{},
+				ProvidedMetrics: Metrics{},
+				ComputedMetrics: Metrics{},
+			},
+		}},
+	}
+
+	b := &bytes.Buffer{}
+	if err := ds.Export(b); err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	exported := b.String()
+	for _, s := range []string{"NaN", "+Inf", "-Inf", "level:GB"} {
+		if !strings.Contains(exported, s) {
+			t.Errorf("exported CSV does not contain %q:\n%s", s, exported)
+		}
+	}
+
+	imported, err := Import(strings.NewReader(exported))
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	if len(imported) != len(ds) {
+		t.Fatalf("imported %d references, want %d", len(imported), len(ds))
+	}
+	for ri, ref := range ds {
+		if imported[ri].Path != ref.Path || len(imported[ri].Distorted) != len(ref.Distorted) {
+			t.Fatalf("reference %d: imported %q with %d distorted images, want %q with %d", ri, imported[ri].Path, len(imported[ri].Distorted), ref.Path, len(ref.Distorted))
+		}
+		for di, want := range ref.Distorted {
+			got := imported[ri].Distorted[di]
+			if got.Path != want.Path {
+				t.Errorf("distortion %d/%d: path %q, want %q", ri, di, got.Path, want.Path)
+			}
+			if !reflect.DeepEqual(got.DissortionsInfo, want.DissortionsInfo) {
+				t.Errorf("%s: distortions %#v, want %#v", want.Path, got.DissortionsInfo, want.DissortionsInfo)
+			}
+			if !equalMetrics(got.ProvidedMetrics, want.ProvidedMetrics) {
+				t.Errorf("%s: provided metrics %v, want %v", want.Path, got.ProvidedMetrics, want.ProvidedMetrics)
+			}
+			if !equalMetrics(got.ComputedMetrics, want.ComputedMetrics) {
+				t.Errorf("%s: computed metrics %v, want %v", want.Path, got.ComputedMetrics, want.ComputedMetrics)
+			}
+		}
+	}
+
+	// Exported imported dataset is the same.
+	b2 := &bytes.Buffer{}
+	if err := imported.Export(b2); err != nil {
+		t.Fatalf("Export of imported dataset error: %v", err)
+	}
+	if b2.String() != exported {
+		t.Errorf("export of imported dataset differs:\n%s\nwant:\n%s", b2, exported)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	for name, csv := range map[string]string{
+		"empty":              "",
+		"no distorted":       "reference,mos\nimg01.bmp,1\n",
+		"invalid value":      "reference,distorted,mos\nimg01.bmp,img01_1.bmp,high\n",
+		"invalid distortion": "reference,distorted,distortions\nimg01.bmp,img01_1.bmp,XY1\n",
+	} {
+		if _, err := Import(strings.NewReader(csv)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package dataset
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Group is a named subset of dataset.
+type Group struct {
+	Name    string
+	Dataset Dataset
+}
+
+// Filter returns dataset with distortions for which keep returns true. References without kept distortions are omitted.
+// Distortions are copied, but their metrics maps are shared with d.
+func (d Dataset) Filter(keep func(ref Reference, dis Distortion) bool) Dataset {
+	res := Dataset{}
+	for _, ref := range d {
+		kept := []Distortion{}
+		for _, dis := range ref.Distorted {
+			if keep(ref, dis) {
+				kept = append(kept, dis)
+			}
+		}
+		if len(kept) > 0 {
+			res = append(res, Reference{Path: ref.Path, Distorted: kept})
+		}
+	}
+	return res
+}
+
+// DistortedCount returns number of distorted images in dataset.
+func (d Dataset) DistortedCount() int {
+	n := 0
+	for _, ref := range d {
+		n += len(ref.Distorted)
+	}
+	return n
+}
+
+// GroupByReference returns group for every reference image, named by reference image file name without extension.
+func (d Dataset) GroupByReference() []Group {
+	res := []Group{}
+	for _, ref := range d {
+		name := strings.TrimSuffix(filepath.Base(ref.Path), filepath.Ext(ref.Path))
+		res = append(res, Group{name, Dataset{ref}})
+	}
+	return res
+}
+
+// GroupByDistortionKind returns group for every distortion kind in DistortionKinds order, containing distortions with that kind applied.
+// Multiply distorted images are in more groups. Distortions with unknown info are not in any group, empty groups are omitted.
+func (d Dataset) GroupByDistortionKind() []Group {
+	res := []Group{}
+	for _, k := range DistortionKinds {
+		g := d.Filter(func(_ Reference, dis Distortion) bool {
+			return dis.DissortionsInfo.Has(k)
+		})
+		if len(g) > 0 {
+			res = append(res, Group{k.String(), g})
+		}
+	}
+	return res
+}
+
+// GroupByDistortionCount returns groups of distortions with the same number of applied distortion kinds, sorted by the number.
+// Groups are named by the number (e.g. "2 distortions"). Distortions with unknown info are not in any group.
+func (d Dataset) GroupByDistortionCount() []Group {
+	counts := map[int]bool{}
+	for _, ref := range d {
+		for _, dis := range ref.Distorted {
+			if dis.DissortionsInfo != nil {
+				counts[len(dis.DissortionsInfo)] = true
+			}
+		}
+	}
+	sorted := []int{}
+	for c := range counts {
+		sorted = append(sorted, c)
+	}
+	sort.Ints(sorted)
+
+	res := []Group{}
+	for _, c := range sorted {
+		g := d.Filter(func(_ Reference, dis Distortion) bool {
+			return dis.DissortionsInfo != nil && len(dis.DissortionsInfo) == c
+		})
+		name := fmt.Sprintf("%d distortions", c)
+		if c == 1 {
+			name = "1 distortion"
+		}
+		res = append(res, Group{name, g})
+	}
+	return res
+}
@@ -0,0 +1,113 @@
+package dataset
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Returns dataset with references "a" and "b" and distortions named by their info (nil info as "unknown", empty as "none").
+func groupTestDataset() Dataset {
+	dis := func(info DistortionsInfo) Distortion {
+		return Distortion{Path: info.String() + ".bmp", DissortionsInfo: info}
+	}
+	return Dataset{
+		{Path: "refs/a.bmp", Distorted: []Distortion{
+			dis(DistortionsInfo{{JPEG, 2}}),
+			dis(nil),
+			dis(DistortionsInfo{{GaussianBlur, 1}, {JPEG, 1}}),
+		}},
+		{Path: "refs/b.png", Distorted: []Distortion{
+			dis(DistortionsInfo{}),
+			dis(DistortionsInfo{{GaussianNoise, 3}, {GaussianBlur, 2}, {JPEG2000, 1}}),
+			dis(DistortionsInfo{{GaussianBlur, 3}}),
+		}},
+	}
+}
+
+// Returns groups as "name: ref/distortion,..." strings, with references and distortions named by base file names without extensions.
+func groupsSummary(groups []Group) []string {
+	base := func(path string) string {
+		return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	res := []string{}
+	for _, g := range groups {
+		members := []string{}
+		for _, ref := range g.Dataset {
+			for _, dis := range ref.Distorted {
+				members = append(members, base(ref.Path)+"/"+base(dis.Path))
+			}
+		}
+		res = append(res, g.Name+": "+strings.Join(members, ","))
+	}
+	return res
+}
+
+func TestFilter(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		keep func(Reference, Distortion) bool
+		want []string
+	}{
+		{"all", func(Reference, Distortion) bool { return true }, []string{"all: a/JPEG2,a/unknown,a/GB1+JPEG1,b/none,b/GN3+GB2+JP2K1,b/GB3"}},
+		{"none", func(Reference, Distortion) bool { return false }, []string{"none: "}},
+		{"unknown", func(_ Reference, dis Distortion) bool { return dis.DissortionsInfo == nil }, []string{"unknown: a/unknown"}},
+		{"empty", func(_ Reference, dis Distortion) bool {
+			return dis.DissortionsInfo != nil && len(dis.DissortionsInfo) == 0
+		}, []string{"empty: b/none"}},
+		{"reference b", func(ref Reference, _ Distortion) bool { return ref.Path == "refs/b.png" }, []string{"reference b: b/none,b/GN3+GB2+JP2K1,b/GB3"}},
+	} {
+		ds := groupTestDataset().Filter(c.keep)
+		if got := groupsSummary([]Group{{c.name, ds}}); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Filter %s = %q, want %q", c.name, got, c.want)
+		}
+		for _, ref := range ds {
+			if len(ref.Distorted) == 0 {
+				t.Errorf("Filter %s kept reference %s without distortions", c.name, ref.Path)
+			}
+		}
+	}
+}
+
+func TestFilterSharesMetrics(t *testing.T) {
+	ds := Dataset{{Path: "a.bmp", Distorted: []Distortion{{Path: "d.bmp", ComputedMetrics: Metrics{}}}}}
+	filtered := ds.Filter(func(Reference, Distortion) bool { return true })
+	filtered[0].Distorted[0].ComputedMetrics["PSNR"] = 1
+	if _, ok := ds[0].Distorted[0].ComputedMetrics["PSNR"]; !ok {
+		t.Error("metrics of filtered distortion are not shared with original dataset")
+	}
+}
+
+func TestGroupings(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		group func(Dataset) []Group
+		ds    Dataset
+		want  []string
+	}{
+		{"GroupByReference", Dataset.GroupByReference, groupTestDataset(), []string{
+			"a: a/JPEG2,a/unknown,a/GB1+JPEG1",
+			"b: b/none,b/GN3+GB2+JP2K1,b/GB3",
+		}},
+		{"GroupByReference nil", Dataset.GroupByReference, nil, []string{}},
+		{"GroupByDistortionKind", Dataset.GroupByDistortionKind, groupTestDataset(), []string{
+			"GN: b/GN3+GB2+JP2K1",
+			"GB: a/GB1+JPEG1,b/GN3+GB2+JP2K1,b/GB3",
+			"JPEG: a/JPEG2,a/GB1+JPEG1",
+			"JP2K: b/GN3+GB2+JP2K1",
+		}},
+		{"GroupByDistortionKind nil", Dataset.GroupByDistortionKind, nil, []string{}},
+		{"GroupByDistortionCount", Dataset.GroupByDistortionCount, groupTestDataset(), []string{
+			"0 distortions: b/none",
+			"1 distortion: a/JPEG2,b/GB3",
+			"2 distortions: a/GB1+JPEG1",
+			"3 distortions: b/GN3+GB2+JP2K1",
+		}},
+		{"GroupByDistortionCount nil", Dataset.GroupByDistortionCount, nil, []string{}},
+	} {
+		if got := groupsSummary(c.group(c.ds)); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
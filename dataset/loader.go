@@ -0,0 +1,171 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DatasetLoader loads dataset from directory.
+type DatasetLoader interface {
+	Load(path string) (Dataset, error)
+}
+
+// LoaderFunc is an adapter to allow the use of ordinary functions as dataset loaders.
+type LoaderFunc func(path string) (Dataset, error)
+
+// Load returns f(path).
+func (f LoaderFunc) Load(path string) (Dataset, error) {
+	return f(path)
+}
+
+// Loaders contains available dataset loaders by dataset name. Loader "export" loads dataset exported by Dataset.Export(...) from file (not directory).
+// Loaded datasets have subjective scores in provided metrics "mos" (higher value means better quality) and "mos_std" (if available).
+// For datasets providing DMOS, original values are in provided metric "dmos" and "mos" holds negated DMOS.
+var Loaders = map[string]DatasetLoader{
+	"MDID":      LoaderFunc(LoadMDID),
+	"TID2008":   LoaderFunc(LoadTID),
+	"TID2013":   LoaderFunc(LoadTID),
+	"LIVE":      LoaderFunc(LoadLIVE),
+	"CSIQ":      LoaderFunc(LoadCSIQ),
+	"KADID-10k": LoaderFunc(LoadKADID),
+	"export":    LoaderFunc(ImportFile),
+}
+
+// LoaderNames returns sorted names of available dataset loaders.
+func LoaderNames() []string {
+	res := make([]string, 0, len(Loaders))
+	for n := range Loaders {
+		res = append(res, n)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Builds dataset from distorted images added with their reference images. References are in order of first addition.
+type datasetBuilder struct {
+	dataset Dataset
+	refs    map[string]int
+}
+
+func newDatasetBuilder() *datasetBuilder {
+	return &datasetBuilder{Dataset{}, map[string]int{}}
+}
+
+// Adds distorted image on path with reference image on refPath, distortions info and provided metrics pm.
+func (b *datasetBuilder) add(refPath, path string, info DistortionsInfo, pm Metrics) {
+	b.addDistortion(refPath, Distortion{
+		Path:            path,
+		DissortionsInfo: info,
+		ProvidedMetrics: pm,
+		ComputedMetrics: make(Metrics),
+	})
+}
+
+// Adds distortion dis with reference image on refPath.
+func (b *datasetBuilder) addDistortion(refPath string, dis Distortion) {
+	ri, ok := b.refs[refPath]
+	if !ok {
+		b.dataset = append(b.dataset, Reference{Path: refPath})
+		ri = len(b.dataset) - 1
+		b.refs[refPath] = ri
+	}
+	b.dataset[ri].Distorted = append(b.dataset[ri].Distorted, dis)
+}
+
+// Returns DMOS based provided metrics: "dmos", negated DMOS as "mos" and "mos_std" if std is not NaN.
+func dmosMetrics(dmos, std float64) Metrics {
+	pm := Metrics{"dmos": dmos, "mos": -dmos}
+	if !math.IsNaN(std) {
+		pm["mos_std"] = std
+	}
+	return pm
+}
+
+// Returns lower cased file names => paths of files in dir and its subdirectories.
+func filesIndex(dir string) (map[string]string, error) {
+	res := map[string]string{}
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			res[strings.ToLower(fi.Name())] = path
+		}
+		return nil
+	})
+	return res, err
+}
+
+// Reads CSV file with header and returns records as column name => value maps. Column names are lower cased and trimmed.
+func readCSVRecords(path string) ([]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header error: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	res := []map[string]string{}
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading csv error: %w", err)
+		}
+		rec := map[string]string{}
+		for i, v := range row {
+			if i < len(header) {
+				rec[header[i]] = strings.TrimSpace(v)
+			}
+		}
+		res = append(res, rec)
+	}
+	return res, nil
+}
+
+// Returns distortions info of distortion type number t at level l, using kinds (type number => kind) mapping.
+// Returns nil if t or l is not a number or type has no corresponding MDID distortion kind.
+func numberedDistortionsInfo(kinds map[int]DistortionKind, t, l string) DistortionsInfo {
+	typ, err := strconv.Atoi(t)
+	if err != nil {
+		return nil
+	}
+	level, err := strconv.Atoi(l)
+	if err != nil {
+		return nil
+	}
+	kind, ok := kinds[typ]
+	if !ok {
+		return nil
+	}
+	return DistortionsInfo{{kind, level}}
+}
+
+// Returns s converted to float64, or NaN (with logged error) if s is not a number.
+func parseFloatOrNaN(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		log.Printf("\tconverting value %q to float64 error: %v", s, err)
+		return math.NaN()
+	}
+	return v
+}
@@ -0,0 +1,190 @@
+package dataset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Writes files with content (file path relative to dir => content) into dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for f, content := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Returns loaded distortions as "reference distorted info provided metrics" strings, paths are relative to dir.
+func loadedSummary(t *testing.T, dir string, ds Dataset) []string {
+	t.Helper()
+	rel := func(path string) string {
+		r, err := filepath.Rel(dir, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return filepath.ToSlash(r)
+	}
+	res := []string{}
+	for _, ref := range ds {
+		for _, dis := range ref.Distorted {
+			res = append(res, fmt.Sprintf("%s %s %v %v", rel(ref.Path), rel(dis.Path), dis.DissortionsInfo, map[string]float64(dis.ProvidedMetrics)))
+		}
+	}
+	return res
+}
+
+func TestLoadTID(t *testing.T) {
+	dir := t.TempDir()
+	writeEmptyFiles(t, dir,
+		"reference_images/I01.BMP",
+		"reference_images/i02.bmp",
+		"distorted_images/i01_01_1.bmp",
+		"distorted_images/i01_02_5.bmp",
+		"distorted_images/i02_08_3.bmp",
+		"distorted_images/i02_11_2.bmp",
+	)
+	writeFiles(t, dir, map[string]string{
+		// Missing distorted image i03_01_1.bmp is skipped, but its std value is consumed.
+		"mos_with_names.txt": "5.5 i01_01_1.bmp\r\n4 i01_02_5.bmp\r\n3.25 I02_08_3.BMP\r\n6 i03_01_1.bmp\r\n2 i02_11_2.bmp\r\n",
+		"mos_std.txt":        "0.1\n0.2\n0.3\n0.4\n0.5\n",
+	})
+
+	ds, err := LoadTID(dir)
+	if err != nil {
+		t.Fatalf("LoadTID error: %v", err)
+	}
+	want := []string{
+		"reference_images/I01.BMP distorted_images/i01_01_1.bmp GN1 map[mos:5.5 mos_std:0.1]",
+		"reference_images/I01.BMP distorted_images/i01_02_5.bmp unknown map[mos:4 mos_std:0.2]",
+		"reference_images/i02.bmp distorted_images/i02_08_3.bmp GB3 map[mos:3.25 mos_std:0.3]",
+		"reference_images/i02.bmp distorted_images/i02_11_2.bmp JP2K2 map[mos:2 mos_std:0.5]",
+	}
+	if got := loadedSummary(t, dir, ds); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadTID = %q, want %q", got, want)
+	}
+}
+
+func TestLoadLIVE(t *testing.T) {
+	files := map[string]string{
+		"jp2k/info.txt":       "womanhat.bmp img1.bmp 0.5\nparrots.bmp img2.bmp 1\n",
+		"jpeg/info.txt":       "parrots.bmp img2.bmp 20\nparrots.bmp img1.bmp 10\n",
+		"wn/info.txt":         "womanhat.bmp img1.bmp 0.1\n",
+		"gblur/info.txt":      "womanhat.bmp img1.bmp 1\n",
+		"fastfading/info.txt": "parrots.bmp img1.bmp 5\n",
+	}
+	// Values are in order of folders and image numbers, jp2k/img2.bmp is an original.
+	dmos := []float64{30, 0, 40, 50, 60, 70, 80}
+	orgs := []float64{0, 1, 0, 0, 0, 0, 0}
+	want := []string{
+		"refimgs/womanhat.bmp jp2k/img1.bmp JP2K1 map[dmos:30 mos:-30]",
+		"refimgs/womanhat.bmp wn/img1.bmp GN1 map[dmos:60 mos:-60]",
+		"refimgs/womanhat.bmp gblur/img1.bmp GB1 map[dmos:70 mos:-70]",
+		"refimgs/parrots.bmp jpeg/img2.bmp JPEG1 map[dmos:50 mos:-50]",
+		"refimgs/parrots.bmp jpeg/img1.bmp JPEG1 map[dmos:40 mos:-40]",
+		"refimgs/parrots.bmp fastfading/img1.bmp unknown map[dmos:80 mos:-80]",
+	}
+
+	m := matBuilder{binary.LittleEndian}
+	mat := append(m.header(), m.matrix(mxDOUBLE_CLASS, 1, len(dmos), "dmos", miDOUBLE, m.doubles(dmos...))...)
+	mat = append(mat, m.matrix(mxDOUBLE_CLASS, 1, len(orgs), "orgs", miDOUBLE, m.doubles(orgs...))...)
+	csv := "dmos,orgs\n"
+	for i := range dmos {
+		csv += fmt.Sprintf("%v,%v\n", dmos[i], orgs[i])
+	}
+
+	for name, dmosFile := range map[string]map[string]string{
+		"mat": {"dmos.mat": string(mat)},
+		"csv": {"dmos.csv": csv},
+	} {
+		dir := t.TempDir()
+		writeFiles(t, dir, files)
+		writeFiles(t, dir, dmosFile)
+
+		ds, err := LoadLIVE(dir)
+		if err != nil {
+			t.Fatalf("%s: LoadLIVE error: %v", name, err)
+		}
+		if got := loadedSummary(t, dir, ds); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: LoadLIVE = %q, want %q", name, got, want)
+		}
+	}
+
+	if _, err := LoadLIVE(t.TempDir()); err == nil {
+		t.Error("LoadLIVE without dmos file returned nil error")
+	}
+}
+
+func TestLoadCSIQ(t *testing.T) {
+	dir := t.TempDir()
+	writeEmptyFiles(t, dir,
+		"src_imgs/1600.png",
+		"src_imgs/cactus.png",
+		"dst_imgs/awgn/1600.AWGN.1.png",
+		"dst_imgs/fnoise/1600.fnoise.2.png",
+		"dst_imgs/jpeg2000/cactus.jpeg2000.3.png",
+		"dst_imgs/contrast/cactus.contrast.4.png",
+	)
+	writeFiles(t, dir, map[string]string{
+		// Unknown distortion type and missing distorted image are skipped.
+		"dmos.csv": "image,dst_idx,dst_type,dst_lev,dmos_std,dmos\n" +
+			"1600,1,noise,1,0.01,0.06\n" +
+			"1600,5,f noise,2,0.02,0.2\n" +
+			"cactus,3,jpeg 2000,3,0.03,0.3\n" +
+			"cactus,4,contrast,4,,0.4\n" +
+			"cactus,9,unknown,1,0.05,0.5\n" +
+			"cactus,6,blur,1,0.06,0.6\n",
+	})
+
+	ds, err := LoadCSIQ(dir)
+	if err != nil {
+		t.Fatalf("LoadCSIQ error: %v", err)
+	}
+	want := []string{
+		"src_imgs/1600.png dst_imgs/awgn/1600.AWGN.1.png GN1 map[dmos:0.06 mos:-0.06 mos_std:0.01]",
+		"src_imgs/1600.png dst_imgs/fnoise/1600.fnoise.2.png unknown map[dmos:0.2 mos:-0.2 mos_std:0.02]",
+		"src_imgs/cactus.png dst_imgs/jpeg2000/cactus.jpeg2000.3.png JP2K3 map[dmos:0.3 mos:-0.3 mos_std:0.03]",
+		"src_imgs/cactus.png dst_imgs/contrast/cactus.contrast.4.png CC4 map[dmos:0.4 mos:-0.4]",
+	}
+	if got := loadedSummary(t, dir, ds); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadCSIQ = %q, want %q", got, want)
+	}
+}
+
+func TestLoadKADID(t *testing.T) {
+	dir := t.TempDir()
+	writeEmptyFiles(t, dir,
+		"images/I01.png",
+		"images/I01_01_03.png",
+		"images/I01_11_1.png",
+		"images/I01_05_2.png",
+	)
+	writeFiles(t, dir, map[string]string{
+		"dmos.csv": "dist_img,ref_img,dmos,var\n" +
+			"I01_01_03.png,I01.png,3.5,0.25\n" +
+			"I01_11_1.png,I01.png,4,1\n" +
+			"I01_05_2.png,I01.png,2,0\n" +
+			"I01_25_1.png,I01.png,1,0\n",
+	})
+
+	ds, err := LoadKADID(dir)
+	if err != nil {
+		t.Fatalf("LoadKADID error: %v", err)
+	}
+	want := []string{
+		"images/I01.png images/I01_01_03.png GB3 map[mos:3.5 mos_std:0.5]",
+		"images/I01.png images/I01_11_1.png GN1 map[mos:4 mos_std:1]",
+		"images/I01.png images/I01_05_2.png unknown map[mos:2 mos_std:0]",
+	}
+	if got := loadedSummary(t, dir, ds); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadKADID = %q, want %q", got, want)
+	}
+}
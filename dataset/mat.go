@@ -0,0 +1,192 @@
+package dataset
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+)
+
+// MAT-file level 5 data types.
+const (
+	miINT8       = 1
+	miUINT8      = 2
+	miINT16      = 3
+	miUINT16     = 4
+	miINT32      = 5
+	miUINT32     = 6
+	miSINGLE     = 7
+	miDOUBLE     = 9
+	miINT64      = 12
+	miUINT64     = 13
+	miMATRIX     = 14
+	miCOMPRESSED = 15
+)
+
+// MAT-file array classes of numeric arrays (mxDOUBLE_CLASS ... mxUINT64_CLASS).
+const (
+	mxDOUBLE_CLASS = 6
+	mxUINT64_CLASS = 15
+)
+
+// ReadMATFile reads real parts of numeric arrays from MATLAB level 5 MAT-file (e.g. saved by MATLAB with default options, compressed or not).
+// Returns variable name => values in column-major order. Variables of other classes (cells, structures, chars, sparse arrays, ...) are skipped.
+// Using: https://www.mathworks.com/help/pdf_doc/matlab/matfile_format.pdf
+func ReadMATFile(path string) (map[string][]float64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 128 {
+		return nil, errors.New("mat file too short")
+	}
+
+	var order binary.ByteOrder
+	switch string(data[126:128]) {
+	case "IM":
+		order = binary.LittleEndian
+	case "MI":
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("not a level 5 mat file")
+	}
+
+	res := map[string][]float64{}
+	if err := readMATElements(data[128:], order, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Reads data element tag from data, returns element type, data and rest of data after element (including padding).
+func readMATElement(data []byte, order binary.ByteOrder) (typ uint32, payload, rest []byte, err error) {
+	if len(data) < 8 {
+		return 0, nil, nil, errors.New("mat element tag truncated")
+	}
+	tag := order.Uint32(data[0:4])
+	if tag>>16 != 0 {
+		// Small data element format, data packed in tag.
+		size := tag >> 16
+		if size > 4 {
+			return 0, nil, nil, errors.New("invalid mat small element size")
+		}
+		return tag & 0xffff, data[4 : 4+size], data[8:], nil
+	}
+
+	size := order.Uint32(data[4:8])
+	if uint64(size) > uint64(len(data)-8) {
+		return 0, nil, nil, errors.New("mat element truncated")
+	}
+	end := 8 + int(size)
+	payload = data[8:end]
+	// Compressed elements are not padded to 64-bit boundary.
+	if tag != miCOMPRESSED {
+		end = 8 + (int(size)+7)/8*8
+		if end > len(data) {
+			end = len(data)
+		}
+	}
+	return tag, payload, data[end:], nil
+}
+
+// Reads top level data elements from data, storing numeric arrays to res.
+func readMATElements(data []byte, order binary.ByteOrder, res map[string][]float64) error {
+	for len(data) > 0 {
+		typ, payload, rest, err := readMATElement(data, order)
+		if err != nil {
+			return err
+		}
+		data = rest
+
+		switch typ {
+		case miCOMPRESSED:
+			r, err := zlib.NewReader(bytes.NewReader(payload))
+			if err != nil {
+				return fmt.Errorf("mat decompression error: %w", err)
+			}
+			decompressed, err := ioutil.ReadAll(r)
+			if err != nil {
+				return fmt.Errorf("mat decompression error: %w", err)
+			}
+			if err := readMATElements(decompressed, order, res); err != nil {
+				return err
+			}
+		case miMATRIX:
+			name, values, err := readMATMatrix(payload, order)
+			if err != nil {
+				return err
+			}
+			if values != nil {
+				res[name] = values
+			}
+		}
+	}
+	return nil
+}
+
+// Reads array name and real part values from miMATRIX element payload. Values are nil for non numeric arrays.
+func readMATMatrix(data []byte, order binary.ByteOrder) (string, []float64, error) {
+	sub := [4][]byte{}
+	subTypes := [4]uint32{}
+	for i := range sub {
+		if len(data) == 0 {
+			// Empty arrays have no subelements after name.
+			break
+		}
+		typ, payload, rest, err := readMATElement(data, order)
+		if err != nil {
+			return "", nil, err
+		}
+		sub[i], subTypes[i], data = payload, typ, rest
+	}
+	if len(sub[0]) < 8 {
+		return "", nil, errors.New("mat array flags truncated")
+	}
+	class := order.Uint32(sub[0][0:4]) & 0xff
+	name := string(sub[2])
+	if class < mxDOUBLE_CLASS || class > mxUINT64_CLASS {
+		return name, nil, nil
+	}
+	values, err := matNumbers(subTypes[3], sub[3], order)
+	return name, values, err
+}
+
+// Converts numeric data of type typ to float64 values.
+func matNumbers(typ uint32, data []byte, order binary.ByteOrder) ([]float64, error) {
+	sizes := map[uint32]int{miINT8: 1, miUINT8: 1, miINT16: 2, miUINT16: 2, miINT32: 4, miUINT32: 4, miSINGLE: 4, miDOUBLE: 8, miINT64: 8, miUINT64: 8}
+	size, ok := sizes[typ]
+	if !ok {
+		return nil, fmt.Errorf("unsupported mat data type %d", typ)
+	}
+
+	res := make([]float64, len(data)/size)
+	for i := range res {
+		b := data[i*size : (i+1)*size]
+		switch typ {
+		case miINT8:
+			res[i] = float64(int8(b[0]))
+		case miUINT8:
+			res[i] = float64(b[0])
+		case miINT16:
+			res[i] = float64(int16(order.Uint16(b)))
+		case miUINT16:
+			res[i] = float64(order.Uint16(b))
+		case miINT32:
+			res[i] = float64(int32(order.Uint32(b)))
+		case miUINT32:
+			res[i] = float64(order.Uint32(b))
+		case miSINGLE:
+			res[i] = float64(math.Float32frombits(order.Uint32(b)))
+		case miDOUBLE:
+			res[i] = math.Float64frombits(order.Uint64(b))
+		case miINT64:
+			res[i] = float64(int64(order.Uint64(b)))
+		case miUINT64:
+			res[i] = float64(order.Uint64(b))
+		}
+	}
+	return res, nil
+}
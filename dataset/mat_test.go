@@ -0,0 +1,144 @@
+package dataset
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Builds MAT-file level 5 data in byte order, elements are written by methods.
+type matBuilder struct {
+	order binary.ByteOrder
+}
+
+// Returns data element of type typ with payload, padded to 64-bit boundary.
+func (m matBuilder) element(typ uint32, payload []byte) []byte {
+	b := make([]byte, 8, 8+len(payload)+7)
+	m.order.PutUint32(b[0:4], typ)
+	m.order.PutUint32(b[4:8], uint32(len(payload)))
+	b = append(b, payload...)
+	for len(b)%8 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// Returns data element of type typ with at most 4 bytes of payload in small data element format.
+func (m matBuilder) smallElement(typ uint32, payload []byte) []byte {
+	b := make([]byte, 8)
+	m.order.PutUint32(b[0:4], uint32(len(payload))<<16|typ)
+	copy(b[4:], payload)
+	return b
+}
+
+// Returns miMATRIX element of array with class, dimensions rows x cols, name and real part of type typ.
+func (m matBuilder) matrix(class uint32, rows, cols int, name string, typ uint32, real []byte) []byte {
+	flags := make([]byte, 8)
+	m.order.PutUint32(flags[0:4], class)
+	dims := make([]byte, 8)
+	m.order.PutUint32(dims[0:4], uint32(rows))
+	m.order.PutUint32(dims[4:8], uint32(cols))
+
+	payload := append(m.element(miUINT32, flags), m.element(miINT32, dims)...)
+	if len(name) <= 4 {
+		payload = append(payload, m.smallElement(miINT8, []byte(name))...)
+	} else {
+		payload = append(payload, m.element(miINT8, []byte(name))...)
+	}
+	if len(real) <= 4 {
+		payload = append(payload, m.smallElement(typ, real)...)
+	} else {
+		payload = append(payload, m.element(typ, real)...)
+	}
+	return m.element(miMATRIX, payload)
+}
+
+func (m matBuilder) doubles(values ...float64) []byte {
+	b := make([]byte, 8*len(values))
+	for i, v := range values {
+		m.order.PutUint64(b[i*8:], math.Float64bits(v))
+	}
+	return b
+}
+
+// Returns 128 bytes MAT-file header.
+func (m matBuilder) header() []byte {
+	h := make([]byte, 128)
+	copy(h, fmt.Sprintf("%-116s", "MATLAB 5.0 MAT-file, created by goMDID test"))
+	m.order.PutUint16(h[124:126], 0x0100)
+	m.order.PutUint16(h[126:128], 'M'<<8|'I')
+	return h
+}
+
+func TestReadMATFile(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		m := matBuilder{order}
+		data := m.header()
+		// Double 1x3 array with name in small element.
+		data = append(data, m.matrix(mxDOUBLE_CLASS, 1, 3, "dmos", miDOUBLE, m.doubles(1.5, -2, math.Inf(1)))...)
+		// Double class array stored as uint8 values in small element (as MATLAB saves integer valued doubles).
+		data = append(data, m.matrix(mxDOUBLE_CLASS, 3, 1, "orgs", miUINT8, []byte{0, 1, 255})...)
+		// Char array is skipped.
+		data = append(data, m.matrix(4, 1, 3, "text", miUINT16, []byte{0, 'a', 0, 'b', 0, 'c'})...)
+		// Compressed int16 array with long name.
+		int16s := make([]byte, 4)
+		order.PutUint16(int16s[0:2], uint16(0xffff)) // -1
+		order.PutUint16(int16s[2:4], 300)
+		z := &bytes.Buffer{}
+		zw := zlib.NewWriter(z)
+		zw.Write(m.matrix(mxDOUBLE_CLASS, 2, 1, "compressed", miINT16, int16s))
+		zw.Close()
+		compressed := make([]byte, 8, 8+z.Len())
+		order.PutUint32(compressed[0:4], miCOMPRESSED)
+		order.PutUint32(compressed[4:8], uint32(z.Len()))
+		data = append(data, append(compressed, z.Bytes()...)...)
+
+		path := filepath.Join(t.TempDir(), "test.mat")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		vars, err := ReadMATFile(path)
+		if err != nil {
+			t.Fatalf("%v: ReadMATFile error: %v", order, err)
+		}
+		want := map[string][]float64{
+			"dmos":       {1.5, -2, math.Inf(1)},
+			"orgs":       {0, 1, 255},
+			"compressed": {-1, 300},
+		}
+		if !reflect.DeepEqual(vars, want) {
+			t.Errorf("%v: ReadMATFile = %v, want %v", order, vars, want)
+		}
+	}
+}
+
+func TestReadMATFileErrors(t *testing.T) {
+	m := matBuilder{binary.LittleEndian}
+	truncated := append(m.header(), m.matrix(mxDOUBLE_CLASS, 1, 2, "x", miDOUBLE, m.doubles(1, 2))...)
+	truncated = truncated[:len(truncated)-8]
+	notMAT := make([]byte, 200)
+
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{
+		"short":     m.header()[:100],
+		"not mat":   notMAT,
+		"truncated": truncated,
+	} {
+		path := filepath.Join(dir, "test.mat")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadMATFile(path); err == nil {
+			t.Errorf("%s file: no error", name)
+		}
+	}
+	if _, err := ReadMATFile(filepath.Join(dir, "missing.mat")); err == nil {
+		t.Errorf("missing file: no error")
+	}
+}
@@ -0,0 +1,336 @@
+package dataset
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/jezek/goMDID/metrics"
+)
+
+// DefaultStoreName is default file name of metrics store in dataset directory.
+const DefaultStoreName = "computed_metrics.jsonl"
+
+// Store is a persistent store of computed metrics values in JSON-lines file.
+// Values are keyed by hashes of reference and distorted image file contents, metric name and metric version (metrics.Info.Version), so renamed or moved images do not need recomputing and changed images or metrics are recomputed.
+// New values are appended to file, file is compacted (rewritten with current values only) when it contains superseded or removed values.
+type Store struct {
+	path    string
+	entries map[storeKey]float64
+	added   []storeEntry
+	// File contains superseded or removed values and has to be rewritten.
+	rewrite bool
+
+	mu     sync.Mutex
+	hashes map[string]string // file path => content hash
+}
+
+type storeKey struct {
+	reference, distorted, metric, version string
+}
+
+// Line of store file.
+type storeEntry struct {
+	Reference string     `json:"reference"`
+	Distorted string     `json:"distorted"`
+	Metric    string     `json:"metric"`
+	Version   string     `json:"version"`
+	Value     storeValue `json:"value"`
+}
+
+// Float value which can be stored to JSON even if it is NaN or infinite (as string).
+type storeValue float64
+
+func (v storeValue) MarshalJSON() ([]byte, error) {
+	f := float64(v)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return json.Marshal(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return json.Marshal(f)
+}
+
+func (v *storeValue) UnmarshalJSON(data []byte) error {
+	var f float64
+	if err := json.Unmarshal(data, &f); err == nil {
+		*v = storeValue(f)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*v = storeValue(f)
+	return nil
+}
+
+// OpenStore returns store with entries loaded from file on path. If file does not exist, store is empty and file is created on first Save().
+// Invalid lines are logged and skipped, later entries override earlier ones with the same key.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, entries: map[storeKey]float64{}, hashes: map[string]string{}}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening store error: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	line, loaded := 0, 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e storeEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			log.Printf("Invalid store %s line %d: %v", path, line, err)
+			continue
+		}
+		s.entries[storeKey{e.Reference, e.Distorted, e.Metric, e.Version}] = float64(e.Value)
+		loaded++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading store error: %w", err)
+	}
+	s.rewrite = loaded > len(s.entries)
+	return s, nil
+}
+
+// Len returns number of values in store.
+func (s *Store) Len() int {
+	return len(s.entries)
+}
+
+// Get returns stored value of metric with version for reference and distorted images with content hashes ref and dis.
+func (s *Store) Get(ref, dis, metric, version string) (float64, bool) {
+	v, ok := s.entries[storeKey{ref, dis, metric, version}]
+	return v, ok
+}
+
+// Put sets value of metric with version for reference and distorted images with content hashes ref and dis. Value is written to file by Save().
+func (s *Store) Put(ref, dis, metric, version string, value float64) {
+	if _, ok := s.entries[storeKey{ref, dis, metric, version}]; ok {
+		s.rewrite = true
+	}
+	s.entries[storeKey{ref, dis, metric, version}] = value
+	s.added = append(s.added, storeEntry{ref, dis, metric, version, storeValue(value)})
+}
+
+// RemoveStale removes values of metric stored with other version than version and returns number of removed values.
+// Removed values are deleted from file by next Save().
+func (s *Store) RemoveStale(metric, version string) int {
+	removed := 0
+	for k := range s.entries {
+		if k.metric == metric && k.version != version {
+			delete(s.entries, k)
+			removed++
+		}
+	}
+	if removed > 0 {
+		s.rewrite = true
+	}
+	return removed
+}
+
+// Save appends values put to store since last save to store file.
+// If file contains superseded or removed values, it is compacted instead, i.e. rewritten with current values only.
+func (s *Store) Save() error {
+	if s.rewrite {
+		return s.compact()
+	}
+	if len(s.added) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening store error: %w", err)
+	}
+	if err := writeStoreEntries(f, s.added); err != nil {
+		f.Close()
+		return err
+	}
+	s.added = nil
+	return f.Close()
+}
+
+// Rewrites store file with current values, sorted by metric, version and image hashes. New file is written next to store file and renamed, so store file is not lost if writing fails.
+func (s *Store) compact() error {
+	entries := make([]storeEntry, 0, len(s.entries))
+	for k, v := range s.entries {
+		entries = append(entries, storeEntry{k.reference, k.distorted, k.metric, k.version, storeValue(v)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		if a.Reference != b.Reference {
+			return a.Reference < b.Reference
+		}
+		return a.Distorted < b.Distorted
+	})
+
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("opening store error: %w", err)
+	}
+	if err := writeStoreEntries(f, entries); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing store error: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("replacing store error: %w", err)
+	}
+	s.added, s.rewrite = nil, false
+	return nil
+}
+
+// Writes entries to w as JSON lines.
+func writeStoreEntries(w io.Writer, entries []storeEntry) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("encoding store entry error: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing store error: %w", err)
+	}
+	return nil
+}
+
+// Hash returns hex encoded SHA-256 hash of file content on path. Hashes are cached by path for store lifetime.
+func (s *Store) Hash(path string) (string, error) {
+	s.mu.Lock()
+	h, ok := s.hashes[path]
+	s.mu.Unlock()
+	if ok {
+		return h, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	h = hex.EncodeToString(hash.Sum(nil))
+
+	s.mu.Lock()
+	s.hashes[path] = h
+	s.mu.Unlock()
+	return h, nil
+}
+
+// ComputeMetricsStored computes metrics from list as ComputeMetrics(...), but values found in store are loaded instead of computing.
+// Metrics from list are described by infos (list name => info), stored values are keyed by info name and version (info config is descriptive only and is not part of the key), so values computed by other version are recomputed.
+// Values of listed metrics stored with other versions are removed from store. Newly computed values are saved to store. Progress counts only computed metrics.
+func (d Dataset) ComputeMetricsStored(store *Store, infos map[string]metrics.Info, list []string, workers int, progress func(done, total int)) error {
+	ms := map[string]metrics.Metric{}
+	for _, m := range list {
+		info, ok := infos[m]
+		if !ok {
+			return fmt.Errorf("no info for metric %s", m)
+		}
+		ms[m] = info.Metric
+	}
+
+	for _, m := range list {
+		info := infos[m]
+		if n := store.RemoveStale(info.Name, strconv.Itoa(info.Version)); n > 0 {
+			log.Printf("Removed %d stale values of metric %s from store", n, info.Name)
+		}
+	}
+
+	// Content hashes of reference and distorted images, empty if image could not be read.
+	type hashes struct{ ref, dis string }
+	hs := make([][]hashes, len(d))
+	for ri, ref := range d {
+		refHash, err := store.Hash(ref.Path)
+		if err != nil {
+			log.Printf("Could not hash image: %v", err)
+		}
+		hs[ri] = make([]hashes, len(ref.Distorted))
+		for di, dis := range ref.Distorted {
+			disHash, err := store.Hash(dis.Path)
+			if err != nil {
+				log.Printf("Could not hash image: %v", err)
+			}
+			hs[ri][di] = hashes{refHash, disHash}
+		}
+	}
+
+	// Load stored values, remember missing ones.
+	type job struct {
+		ri, di int
+		metric string
+	}
+	missing := map[job]bool{}
+	for ri, ref := range d {
+		for di := range ref.Distorted {
+			dis := &ref.Distorted[di]
+			h := hs[ri][di]
+			for _, m := range list {
+				info := infos[m]
+				if v, ok := store.Get(h.ref, h.dis, info.Name, strconv.Itoa(info.Version)); ok && h.ref != "" && h.dis != "" {
+					if dis.ComputedMetrics == nil {
+						dis.ComputedMetrics = Metrics{}
+					}
+					dis.ComputedMetrics[m] = v
+				} else {
+					missing[job{ri, di, m}] = true
+				}
+			}
+		}
+	}
+
+	err := d.computeMetrics(ms, list, workers, progress, func(ri, di int, m string) bool {
+		return missing[job{ri, di, m}]
+	})
+	if err != nil {
+		return err
+	}
+
+	for ri, ref := range d {
+		for di, dis := range ref.Distorted {
+			h := hs[ri][di]
+			for _, m := range list {
+				if v, ok := dis.ComputedMetrics[m]; ok && missing[job{ri, di, m}] && h.ref != "" && h.dis != "" {
+					info := infos[m]
+					store.Put(h.ref, h.dis, info.Name, strconv.Itoa(info.Version), v)
+				}
+			}
+		}
+	}
+	return store.Save()
+}
@@ -0,0 +1,140 @@
+package dataset
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jezek/goMDID/metrics"
+)
+
+// Returns number of lines in file on path.
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
+
+func TestComputeMetricsStored(t *testing.T) {
+	dir := t.TempDir()
+	ds := testDataset(t, dir, 3)
+	path := filepath.Join(dir, DefaultStoreName)
+
+	computed := 0
+	info := metrics.Info{Name: "counted", Version: 1, Config: map[string]float64{"K": 1}, Metric: metrics.MetricFunc(func(a, b image.Image) (float64, error) {
+		computed++
+		return metrics.MSEErr(a, b)
+	})}
+	compute := func() {
+		t.Helper()
+		store, err := OpenStore(path)
+		if err != nil {
+			t.Fatalf("OpenStore error: %v", err)
+		}
+		// Workers run metrics concurrently, single worker keeps counter safe.
+		if err := ds.ComputeMetricsStored(store, map[string]metrics.Info{"m": info}, []string{"m"}, 1, nil); err != nil {
+			t.Fatalf("ComputeMetricsStored error: %v", err)
+		}
+	}
+
+	compute()
+	if computed != 3 {
+		t.Fatalf("computed %d values on empty store, want 3", computed)
+	}
+	values := ds.ComputedMetricsByName("m")
+
+	// Reopened store provides all values.
+	for ri := range ds {
+		for di := range ds[ri].Distorted {
+			ds[ri].Distorted[di].ComputedMetrics = nil
+		}
+	}
+	compute()
+	if computed != 3 {
+		t.Errorf("computed %d values after reopening store, want 3", computed)
+	}
+	for i, v := range ds.ComputedMetricsByName("m") {
+		if v != values[i] {
+			t.Errorf("distortion %d: stored value %v, computed %v", i, v, values[i])
+		}
+	}
+	if n := countLines(t, path); n != 3 {
+		t.Errorf("store has %d lines, want 3", n)
+	}
+
+	// Bumped version makes stored values stale, they are recomputed and replaced in file.
+	info.Version = 2
+	compute()
+	if computed != 6 {
+		t.Errorf("computed %d values after version bump, want 6", computed)
+	}
+	if n := countLines(t, path); n != 3 {
+		t.Errorf("store has %d lines after version bump, want 3 (compacted)", n)
+	}
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore error: %v", err)
+	}
+	if store.Len() != 3 {
+		t.Errorf("store has %d values, want 3", store.Len())
+	}
+
+	// Config is descriptive only, it is not part of the store key.
+	info.Config["K"] = 2
+	compute()
+	if computed != 6 {
+		t.Errorf("computed %d values after config change, want 6", computed)
+	}
+}
+
+func TestStoreCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultStoreName)
+	put := func(value float64) {
+		t.Helper()
+		store, err := OpenStore(path)
+		if err != nil {
+			t.Fatalf("OpenStore error: %v", err)
+		}
+		store.Put("ref", "dis", "m", "1", value)
+		store.Put("ref", "dis2", "m", "1", math.Inf(-1))
+		if err := store.Save(); err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+	}
+
+	put(1)
+	put(math.NaN())
+	if n := countLines(t, path); n != 2 {
+		t.Errorf("store has %d lines after overwriting values, want 2", n)
+	}
+
+	// File with superseded lines (e.g. appended by other process) is compacted by next save.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"reference":"ref","distorted":"dis","metric":"m","version":"1","value":3}` + "\n")
+	f.Close()
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore error: %v", err)
+	}
+	if v, ok := store.Get("ref", "dis", "m", "1"); !ok || v != 3 {
+		t.Errorf("Get = %v, %v, want last stored value 3", v, ok)
+	}
+	if v, ok := store.Get("ref", "dis2", "m", "1"); !ok || !math.IsInf(v, -1) {
+		t.Errorf("Get = %v, %v, want -Inf", v, ok)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	if n := countLines(t, path); n != 2 {
+		t.Errorf("store has %d lines after compaction, want 2", n)
+	}
+}
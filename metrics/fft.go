@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Returns twiddle factors exp(-+2*pi*i*k/n) for discrete Fourier transform of length n.
+func fftTwiddles(n int, inverse bool) []complex128 {
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+	tw := make([]complex128, n)
+	for k := range tw {
+		tw[k] = cmplx.Rect(1, sign*2*math.Pi*float64(k)/float64(n))
+	}
+	return tw
+}
+
+// Returns discrete Fourier transform of x using recursive mixed radix Cooley-Tukey algorithm.
+// Prime factors are transformed by naive DFT, so the transform takes O(n·p) operations, where p is the largest prime factor of n (O(n²) for prime n).
+// Input is not padded to a faster length, because padding would change the spectrum and so results of FSIM compared to reference implementation.
+// Twiddle factors tw are from fftTwiddles(len(x)*stride, ...), inverse transform is computed without 1/len(x) normalization.
+func fft(x []complex128, tw []complex128, stride int) []complex128 {
+	n := len(x)
+	res := make([]complex128, n)
+	if n <= 1 {
+		copy(res, x)
+		return res
+	}
+
+	p := 2
+	for ; p*p <= n && n%p != 0; p++ {
+	}
+	if n%p != 0 {
+		p = n // prime
+	}
+	m := n / p
+
+	// Transforms of p decimated subsequences.
+	subs := make([][]complex128, p)
+	if m > 1 {
+		s := make([]complex128, m)
+		for r := range subs {
+			for k := range s {
+				s[k] = x[k*p+r]
+			}
+			subs[r] = fft(s, tw, stride*p)
+		}
+	} else {
+		for r := range subs {
+			subs[r] = []complex128{x[r]}
+		}
+	}
+
+	for k := range res {
+		sum := subs[0][k%m]
+		for r := 1; r < p; r++ {
+			sum += subs[r][k%m] * tw[(r*k%n)*stride]
+		}
+		res[k] = sum
+	}
+	return res
+}
+
+// Returns 2D discrete Fourier transform of w x h matrix x (row major). If inverse is true, normalized inverse transform is computed.
+func fft2(x []complex128, w, h int, inverse bool) []complex128 {
+	res := make([]complex128, len(x))
+	tw := fftTwiddles(w, inverse)
+	for y := 0; y < h; y++ {
+		copy(res[y*w:(y+1)*w], fft(x[y*w:(y+1)*w], tw, 1))
+	}
+
+	col := make([]complex128, h)
+	tw = fftTwiddles(h, inverse)
+	for x := 0; x < w; x++ {
+		for y := range col {
+			col[y] = res[y*w+x]
+		}
+		for y, v := range fft(col, tw, 1) {
+			res[y*w+x] = v
+		}
+	}
+
+	if inverse {
+		n := complex(float64(w*h), 0)
+		for i := range res {
+			res[i] /= n
+		}
+	}
+	return res
+}
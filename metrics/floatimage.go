@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/jezek/goMDID/stats"
+)
+
+// FloatImage is a single channel image with float64 pixel values, used for intermediate results of metrics computations.
+type FloatImage struct {
+	W, H int
+	Pix  []float64
+}
+
+// NewFloatImage returns zero valued float image of size w x h.
+func NewFloatImage(w, h int) *FloatImage {
+	return &FloatImage{W: w, H: h, Pix: make([]float64, w*h)}
+}
+
+// At returns pixel value at position x, y.
+func (f *FloatImage) At(x, y int) float64 {
+	return f.Pix[y*f.W+x]
+}
+
+// Set sets pixel value at position x, y.
+func (f *FloatImage) Set(x, y int, v float64) {
+	f.Pix[y*f.W+x] = v
+}
+
+// Mean returns arithmetic mean of all pixel values.
+func (f *FloatImage) Mean() float64 {
+	return stats.MeanA(f.Pix)
+}
+
+// Gray returns 8-bit gray image, where pixel values are linearly mapped from range min-max to 0-255. Values out of range are clipped.
+func (f *FloatImage) Gray(min, max float64) *image.Gray {
+	res := image.NewGray(image.Rect(0, 0, f.W, f.H))
+	for y := 0; y < f.H; y++ {
+		for x := 0; x < f.W; x++ {
+			v := math.Round((f.At(x, y) - min) / (max - min) * 255)
+			res.SetGray(x, y, color.Gray{uint8(math.Max(0, math.Min(255, v)))})
+		}
+	}
+	return res
+}
+
+// Returns float image from gray image, pixel values are in range 0-255.
+func grayFloat(img *image.Gray) *FloatImage {
+	b := img.Bounds()
+	res := NewFloatImage(b.Dx(), b.Dy())
+	for y := 0; y < res.H; y++ {
+		for x := 0; x < res.W; x++ {
+			res.Set(x, y, float64(img.GrayAt(b.Min.X+x, b.Min.Y+y).Y))
+		}
+	}
+	return res
+}
+
+// Returns new float image with f(a, b) values for every pixel of a, b. Panics with ErrBoundsMismatch if a, b have different dimensions.
+func mergeFloat(a, b *FloatImage, f func(va, vb float64) float64) *FloatImage {
+	return mustImage(mergeFloatErr(a, b, f))
+}
+
+// Returns mergeFloat(...) or ErrBoundsMismatch if a, b have different dimensions.
+func mergeFloatErr(a, b *FloatImage, f func(va, vb float64) float64) (*FloatImage, error) {
+	if a.W != b.W || a.H != b.H {
+		return nil, ErrBoundsMismatch
+	}
+	res := NewFloatImage(a.W, a.H)
+	for i := range res.Pix {
+		res.Pix[i] = f(a.Pix[i], b.Pix[i])
+	}
+	return res, nil
+}
+
+// Returns symmetric (mirrored with edge) index i for length n, as in MATLAB's 'symmetric' padding.
+func symmetricIndex(i, n int) int {
+	for i < 0 || i >= n {
+		if i < 0 {
+			i = -i - 1
+		}
+		if i >= n {
+			i = 2*n - i - 1
+		}
+	}
+	return i
+}
+
+// Returns 2D correlation of img with separable kernel k (k x k outer product), keeping only the parts computed without padding (MATLAB's filter2(..., 'valid')).
+func filterValid(img *FloatImage, k []float64) *FloatImage {
+	n := len(k)
+	if img.W < n || img.H < n {
+		return NewFloatImage(0, 0)
+	}
+
+	rows := NewFloatImage(img.W-n+1, img.H)
+	for y := 0; y < rows.H; y++ {
+		for x := 0; x < rows.W; x++ {
+			s := 0.0
+			for i, kv := range k {
+				s += kv * img.At(x+i, y)
+			}
+			rows.Set(x, y, s)
+		}
+	}
+
+	res := NewFloatImage(rows.W, img.H-n+1)
+	for y := 0; y < res.H; y++ {
+		for x := 0; x < res.W; x++ {
+			s := 0.0
+			for i, kv := range k {
+				s += kv * rows.At(x, y+i)
+			}
+			res.Set(x, y, s)
+		}
+	}
+	return res
+}
+
+// Returns every f-th pixel of img in both directions, starting with first one (MATLAB's img(1:f:end, 1:f:end)).
+func decimate(img *FloatImage, f int) *FloatImage {
+	res := NewFloatImage((img.W+f-1)/f, (img.H+f-1)/f)
+	for y := 0; y < res.H; y++ {
+		for x := 0; x < res.W; x++ {
+			res.Set(x, y, img.At(x*f, y*f))
+		}
+	}
+	return res
+}
+
+// Padding tells how filters get values of pixels outside of image.
+type padding int
+
+const (
+	symmetricPadding padding = iota // mirrored with edge, as MATLAB's imfilter(..., 'symmetric')
+	zeroPadding                     // zeros, as MATLAB's conv2(...)
+)
+
+// Returns value of pixel x, y of img, pixels outside of img are given by padding pad.
+func (f *FloatImage) atPadded(x, y int, pad padding) float64 {
+	if pad == symmetricPadding {
+		return f.At(symmetricIndex(x, f.W), symmetricIndex(y, f.H))
+	}
+	if x < 0 || x >= f.W || y < 0 || y >= f.H {
+		return 0
+	}
+	return f.At(x, y)
+}
+
+// Returns img low-pass filtered by f x f averaging filter (same size, pixels outside of img given by pad) and then downsampled by factor f using decimate(...).
+// Window of imfilter(..., 'same') and conv2(..., 'same') is the same for average kernel, so for symmetricPadding it is equal to MATLAB's
+//
+//	img = imfilter(img, ones(f,f)/f^2, 'symmetric', 'same'); img = img(1:f:end, 1:f:end);
+//
+// (SSIM, MS-SSIM) and for zeroPadding to
+//
+//	img = conv2(img, fspecial('average', f), 'same'); img = img(1:f:end, 1:f:end);
+//
+// (FSIM, GMSD). If f <= 1, img is returned.
+func averageDownsample(img *FloatImage, f int, pad padding) *FloatImage {
+	if f <= 1 {
+		return img
+	}
+
+	c := (f+1)/2 - 1 // kernel center (0 based) as in imfilter
+	// Only pixels kept by decimate(...) are filtered.
+	filtered := NewFloatImage(img.W, img.H)
+	for y := 0; y < img.H; y += f {
+		for x := 0; x < img.W; x += f {
+			s := 0.0
+			for j := 0; j < f; j++ {
+				for i := 0; i < f; i++ {
+					s += img.atPadded(x+i-c, y+j-c, pad)
+				}
+			}
+			filtered.Set(x, y, s/float64(f*f))
+		}
+	}
+	return decimate(filtered, f)
+}
+
+// Returns 2D convolution of img with 3x3 kernel k (MATLAB's conv2(img, k, 'same')), using zero padding.
+func convolveSame3(img *FloatImage, k [3][3]float64) *FloatImage {
+	res := NewFloatImage(img.W, img.H)
+	for y := 0; y < img.H; y++ {
+		for x := 0; x < img.W; x++ {
+			s := 0.0
+			for j := -1; j <= 1; j++ {
+				for i := -1; i <= 1; i++ {
+					s += k[j+1][i+1] * img.atPadded(x-i, y-j, zeroPadding)
+				}
+			}
+			res.Set(x, y, s)
+		}
+	}
+	return res
+}
@@ -0,0 +1,267 @@
+package metrics
+
+import (
+	"image"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// FSIM (Feature SIMilarity) index and its chromatic extension FSIMc.
+// Using: L. Zhang, L. Zhang, X. Mou and D. Zhang, "FSIM: A Feature Similarity Index for Image Quality Assessment," IEEE Transactions on Image Processing, vol. 20, no. 8, pp. 2378-2386, Aug. 2011.
+// Reference implementation: https://www4.comp.polyu.edu.hk/~cslzhang/IQA/FSIM/FSIM.htm (FeatureSIM.m)
+
+// Default FSIM constants.
+const (
+	FSIMT1     = 0.85 // phase congruency similarity stability constant
+	FSIMT2     = 160  // gradient magnitude similarity stability constant
+	FSIMT3     = 200  // I chrominance similarity stability constant
+	FSIMT4     = 200  // Q chrominance similarity stability constant
+	FSIMLambda = 0.03 // chrominance similarity exponent
+)
+
+// Phase congruency constants, as in phasecong2.m used by reference implementation.
+const (
+	pcScales        = 4      // number of log-Gabor filter scales
+	pcOrients       = 4      // number of filter orientations
+	pcMinWaveLength = 6      // wavelength of smallest scale filter
+	pcMult          = 2      // scaling factor between successive filters
+	pcSigmaOnf      = 0.55   // ratio of the standard deviation of the gaussian describing the log Gabor filter's transfer function in the frequency domain to the filter center frequency
+	pcDThetaOnSigma = 1.2    // ratio of angular interval between filter orientations and the standard deviation of the angular gaussian function used to construct filters in the frequency plane
+	pcK             = 2.0    // number of standard deviations of the noise energy beyond the mean at which we set the noise threshold point
+	pcEpsilon       = 0.0001 // used to prevent division by zero
+)
+
+// Returns frequency coordinate for index i of unshifted (ifftshift-ed) spectrum of length n, as in phasecong2.m.
+func pcFrequency(i, n int) float64 {
+	c := (i + n/2) % n // index in centered range
+	if n%2 == 1 {
+		return float64(c-(n-1)/2) / float64(n-1)
+	}
+	return float64(c-n/2) / float64(n)
+}
+
+// Returns phase congruency map of img, computed using log-Gabor filter bank in frequency domain.
+func phaseCongruency(img *FloatImage) *FloatImage {
+	w, h := img.W, img.H
+	n := w * h
+
+	imagefft := make([]complex128, n)
+	for i, v := range img.Pix {
+		imagefft[i] = complex(v, 0)
+	}
+	imagefft = fft2(imagefft, w, h, false)
+
+	// Radius, angle and lowpass filter of every frequency.
+	radius, sintheta, costheta, lp := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for y := 0; y < h; y++ {
+		fy := pcFrequency(y, h)
+		for x := 0; x < w; x++ {
+			fx := pcFrequency(x, w)
+			i := y*w + x
+			radius[i] = math.Sqrt(fx*fx + fy*fy)
+			lp[i] = 1 / (1 + math.Pow(radius[i]/0.45, 2*15))
+			theta := math.Atan2(-fy, fx)
+			sintheta[i], costheta[i] = math.Sin(theta), math.Cos(theta)
+		}
+	}
+	radius[0] = 1
+
+	logGabor := make([][]float64, pcScales)
+	for s := range logGabor {
+		fo := 1 / (pcMinWaveLength * math.Pow(pcMult, float64(s)))
+		logGabor[s] = make([]float64, n)
+		for i, r := range radius {
+			l := math.Log(r / fo)
+			logGabor[s][i] = math.Exp(-(l*l)/(2*math.Log(pcSigmaOnf)*math.Log(pcSigmaOnf))) * lp[i]
+		}
+		logGabor[s][0] = 0
+	}
+
+	thetaSigma := math.Pi / pcOrients / pcDThetaOnSigma
+	energyAll, anAll := NewFloatImage(w, h), NewFloatImage(w, h)
+	for o := 0; o < pcOrients; o++ {
+		angl := float64(o) * math.Pi / pcOrients
+		spread := make([]float64, n)
+		for i := range spread {
+			ds := sintheta[i]*math.Cos(angl) - costheta[i]*math.Sin(angl)
+			dc := costheta[i]*math.Cos(angl) + sintheta[i]*math.Sin(angl)
+			dtheta := math.Abs(math.Atan2(ds, dc))
+			spread[i] = math.Exp(-(dtheta * dtheta) / (2 * thetaSigma * thetaSigma))
+		}
+
+		sumE, sumO, sumAn := make([]float64, n), make([]float64, n), make([]float64, n)
+		eo := make([][]complex128, pcScales)
+		ifftFilters := make([][]float64, pcScales)
+		emN := 0.0
+		for s := 0; s < pcScales; s++ {
+			filter := make([]complex128, n)
+			for i := range filter {
+				filter[i] = complex(logGabor[s][i]*spread[i], 0)
+			}
+			if s == 0 {
+				for _, f := range filter {
+					emN += real(f) * real(f)
+				}
+			}
+
+			ifftFilters[s] = make([]float64, n)
+			for i, v := range fft2(filter, w, h, true) {
+				ifftFilters[s][i] = real(v) * math.Sqrt(float64(n))
+			}
+
+			for i := range filter {
+				filter[i] *= imagefft[i]
+			}
+			eo[s] = fft2(filter, w, h, true)
+			for i, v := range eo[s] {
+				sumAn[i] += cmplx.Abs(v)
+				sumE[i] += real(v)
+				sumO[i] += imag(v)
+			}
+		}
+
+		// Weighted energy.
+		energy := make([]float64, n)
+		for i := range energy {
+			xEnergy := math.Sqrt(sumE[i]*sumE[i]+sumO[i]*sumO[i]) + pcEpsilon
+			meanE, meanO := sumE[i]/xEnergy, sumO[i]/xEnergy
+			for s := range eo {
+				e, od := real(eo[s][i]), imag(eo[s][i])
+				energy[i] += e*meanE + od*meanO - math.Abs(e*meanO-od*meanE)
+			}
+		}
+
+		// Noise threshold estimated from smallest scale filter responses.
+		e2n := make([]float64, n)
+		for i, v := range eo[0] {
+			a := cmplx.Abs(v)
+			e2n[i] = a * a
+		}
+		sort.Float64s(e2n)
+		medianE2n := e2n[n/2]
+		if n%2 == 0 {
+			medianE2n = (e2n[n/2-1] + e2n[n/2]) / 2
+		}
+		meanE2n := -medianE2n / math.Log(0.5)
+		noisePower := meanE2n / emN
+
+		sumEstSumAn2, sumEstSumAiAj := 0.0, 0.0
+		for i := 0; i < n; i++ {
+			for si := range ifftFilters {
+				sumEstSumAn2 += ifftFilters[si][i] * ifftFilters[si][i]
+				for sj := si + 1; sj < len(ifftFilters); sj++ {
+					sumEstSumAiAj += ifftFilters[si][i] * ifftFilters[sj][i]
+				}
+			}
+		}
+		estNoiseEnergy2 := 2*noisePower*sumEstSumAn2 + 4*noisePower*sumEstSumAiAj
+		tau := math.Sqrt(estNoiseEnergy2 / 2)
+		estNoiseEnergy := tau * math.Sqrt(math.Pi/2)
+		estNoiseEnergySigma := math.Sqrt((2 - math.Pi/2) * tau * tau)
+		t := (estNoiseEnergy + pcK*estNoiseEnergySigma) / 1.7
+
+		for i := range energy {
+			energyAll.Pix[i] += math.Max(energy[i]-t, 0)
+			anAll.Pix[i] += sumAn[i]
+		}
+	}
+
+	return mergeFloat(energyAll, anAll, func(e, an float64) float64 { return e / an })
+}
+
+// Returns Y, I, Q channels of color image img (with RGB values in range 0-255).
+func yiqFloat(img image.Image) (Y, I, Q *FloatImage) {
+	b := img.Bounds()
+	Y, I, Q = NewFloatImage(b.Dx(), b.Dy()), NewFloatImage(b.Dx(), b.Dy()), NewFloatImage(b.Dx(), b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			R, G, B := float64(r>>8), float64(g>>8), float64(bl>>8)
+			Y.Set(x, y, 0.299*R+0.587*G+0.114*B)
+			I.Set(x, y, 0.596*R-0.274*G-0.322*B)
+			Q.Set(x, y, 0.211*R-0.523*G+0.312*B)
+		}
+	}
+	return
+}
+
+// Returns FSIM and FSIMc indexes of the two input color images.
+func fsim(a, b image.Image) (fsim, fsimc float64, err error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, 0, err
+	}
+
+	f := ssimDownsampleFactor(a.Bounds().Dx(), a.Bounds().Dy())
+	Y1, I1, Q1 := yiqFloat(a)
+	Y2, I2, Q2 := yiqFloat(b)
+	Y1, I1, Q1 = averageDownsample(Y1, f, zeroPadding), averageDownsample(I1, f, zeroPadding), averageDownsample(Q1, f, zeroPadding)
+	Y2, I2, Q2 = averageDownsample(Y2, f, zeroPadding), averageDownsample(I2, f, zeroPadding), averageDownsample(Q2, f, zeroPadding)
+	if len(Y1.Pix) == 0 {
+		return 0, 0, ErrImageTooSmall
+	}
+
+	pc1, pc2 := phaseCongruency(Y1), phaseCongruency(Y2)
+
+	// Gradient magnitudes using Scharr operator.
+	dx := [3][3]float64{{3.0 / 16, 0, -3.0 / 16}, {10.0 / 16, 0, -10.0 / 16}, {3.0 / 16, 0, -3.0 / 16}}
+	dy := [3][3]float64{{3.0 / 16, 10.0 / 16, 3.0 / 16}, {0, 0, 0}, {-3.0 / 16, -10.0 / 16, -3.0 / 16}}
+	gm1 := mergeFloat(convolveSame3(Y1, dx), convolveSame3(Y1, dy), math.Hypot)
+	gm2 := mergeFloat(convolveSame3(Y2, dx), convolveSame3(Y2, dy), math.Hypot)
+
+	sim, simc, pcmSum := 0.0, 0.0, 0.0
+	for i := range pc1.Pix {
+		p1, p2 := pc1.Pix[i], pc2.Pix[i]
+		g1, g2 := gm1.Pix[i], gm2.Pix[i]
+		pcSim := (2*p1*p2 + FSIMT1) / (p1*p1 + p2*p2 + FSIMT1)
+		gSim := (2*g1*g2 + FSIMT2) / (g1*g1 + g2*g2 + FSIMT2)
+		pcm := math.Max(p1, p2)
+
+		i1, i2, q1, q2 := I1.Pix[i], I2.Pix[i], Q1.Pix[i], Q2.Pix[i]
+		iSim := (2*i1*i2 + FSIMT3) / (i1*i1 + i2*i2 + FSIMT3)
+		qSim := (2*q1*q2 + FSIMT4) / (q1*q1 + q2*q2 + FSIMT4)
+		// Real part of (possibly negative) power, as in reference implementation.
+		cSim := 0.0
+		if iq := iSim * qSim; iq >= 0 {
+			cSim = math.Pow(iq, FSIMLambda)
+		} else {
+			cSim = math.Pow(-iq, FSIMLambda) * math.Cos(FSIMLambda*math.Pi)
+		}
+
+		sim += gSim * pcSim * pcm
+		simc += gSim * pcSim * cSim * pcm
+		pcmSum += pcm
+	}
+	return sim / pcmSum, simc / pcmSum, nil
+}
+
+// FSIM returns feature similarity index of the two input color images, computed from phase congruency and gradient magnitude of luminance (Y channel of YIQ color space, not rounded to 8 bits).
+// Images are automatically downsampled, as in reference implementation.
+// Phase congruency uses FFT of the downsampled image, its dimensions with large prime factors are slow to transform (see fft(...)).
+func FSIM(a, b image.Image) float64 {
+	return must(FSIMErr(a, b))
+}
+
+// FSIMErr is FSIM(...) returning ErrBoundsMismatch or ErrImageTooSmall instead of panic.
+func FSIMErr(a, b image.Image) (float64, error) {
+	res, _, err := fsim(a, b)
+	return res, err
+}
+
+// FSIMc returns feature similarity index of the two input color images with chrominance information (I and Q channels of YIQ color space) incorporated.
+func FSIMc(a, b image.Image) float64 {
+	return must(FSIMcErr(a, b))
+}
+
+// FSIMcErr is FSIMc(...) returning ErrBoundsMismatch or ErrImageTooSmall instead of panic.
+func FSIMcErr(a, b image.Image) (float64, error) {
+	_, res, err := fsim(a, b)
+	return res, err
+}
+
+func init() {
+	config := map[string]float64{"T1": FSIMT1, "T2": FSIMT2}
+	Register(Info{Name: "FSIM", Version: 1, Direction: HigherIsBetter, Min: 0, Max: 1, Color: ColorImages, Config: config, Metric: MetricFunc(FSIMErr)})
+	config = map[string]float64{"T1": FSIMT1, "T2": FSIMT2, "T3": FSIMT3, "T4": FSIMT4, "lambda": FSIMLambda}
+	Register(Info{Name: "FSIMc", Aliases: []string{"FSIMC"}, Version: 1, Direction: HigherIsBetter, Min: 0, Max: 1, Color: ColorImages, Config: config, Metric: MetricFunc(FSIMcErr)})
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"image"
+	"math"
+
+	"github.com/jezek/goMDID/stats"
+)
+
+// GMSD (Gradient Magnitude Similarity Deviation) index.
+// Using: W. Xue, L. Zhang, X. Mou and A. C. Bovik, "Gradient Magnitude Similarity Deviation: A Highly Efficient Perceptual Image Quality Index," IEEE Transactions on Image Processing, vol. 23, no. 2, pp. 684-695, Feb. 2014.
+// Reference implementation: https://www4.comp.polyu.edu.hk/~cslzhang/IQA/GMSD/GMSD.htm (GMSD.m)
+
+// Default GMSD constants.
+const (
+	GMSDT        = 170 // stability constant
+	GMSDDownStep = 2   // downsampling factor
+)
+
+// GMSMap returns gradient magnitude similarity map of the two input color images, converted to gray images using gray8(...).
+// Images are filtered by 2x2 average filter and downsampled by factor GMSDDownStep, gradients are computed using Prewitt operator.
+func GMSMap(a, b image.Image) *FloatImage {
+	return mustImage(GMSMapErr(a, b))
+}
+
+// GMSMapErr is GMSMap(...) returning ErrBoundsMismatch or ErrImageTooSmall instead of panic.
+func GMSMapErr(a, b image.Image) (*FloatImage, error) {
+	if err := checkBounds(a, b); err != nil {
+		return nil, err
+	}
+
+	Y1 := averageDownsample(grayFloat(gray8(a)), GMSDDownStep, zeroPadding)
+	Y2 := averageDownsample(grayFloat(gray8(b)), GMSDDownStep, zeroPadding)
+	if len(Y1.Pix) == 0 {
+		return nil, ErrImageTooSmall
+	}
+
+	dx := [3][3]float64{{1.0 / 3, 0, -1.0 / 3}, {1.0 / 3, 0, -1.0 / 3}, {1.0 / 3, 0, -1.0 / 3}}
+	dy := [3][3]float64{{1.0 / 3, 1.0 / 3, 1.0 / 3}, {0, 0, 0}, {-1.0 / 3, -1.0 / 3, -1.0 / 3}}
+	gm1 := mergeFloat(convolveSame3(Y1, dx), convolveSame3(Y1, dy), math.Hypot)
+	gm2 := mergeFloat(convolveSame3(Y2, dx), convolveSame3(Y2, dy), math.Hypot)
+
+	return mergeFloat(gm1, gm2, func(g1, g2 float64) float64 {
+		return (2*g1*g2 + GMSDT) / (g1*g1 + g2*g2 + GMSDT)
+	}), nil
+}
+
+// GMSD returns gradient magnitude similarity deviation (standart deviation of GMSMap(...)) of the two input color images.
+// Lower value means better quality, 0 for identical images.
+func GMSD(a, b image.Image) float64 {
+	return must(GMSDErr(a, b))
+}
+
+// GMSDErr is GMSD(...) returning ErrBoundsMismatch or ErrImageTooSmall instead of panic.
+func GMSDErr(a, b image.Image) (float64, error) {
+	gms, err := GMSMapErr(a, b)
+	if err != nil {
+		return 0, err
+	}
+	return stats.Sd(gms.Pix), nil
+}
+
+func init() {
+	Register(Info{
+		Name:      "GMSD",
+		Direction: LowerIsBetter,
+		Min:       0,
+		Max:       1,
+		Color:     GrayImages,
+		Config:    map[string]float64{"T": GMSDT, "downStep": GMSDDownStep},
+		Version:   1,
+		Metric:    MetricFunc(GMSDErr),
+	})
+}
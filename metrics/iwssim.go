@@ -0,0 +1,430 @@
+package metrics
+
+import (
+	"image"
+	"math"
+)
+
+// IW-SSIM (Information content Weighted Structural SIMilarity) index.
+// Using: Z. Wang and Q. Li, "Information content weighting for perceptual image quality assessment," IEEE Transactions on Image Processing, vol. 20, no. 5, pp. 1185-1198, May 2011.
+// Reference implementation: https://ece.uwaterloo.ca/~z70wang/research/iwssim/ (iwssim.m)
+
+// Default IW-SSIM constants.
+const (
+	IWSSIMScales     = 5    // number of pyramid scales, has to be equal to len(MSSSIMWeights)
+	IWSSIMBlockSize  = 3    // size of neighborhood block for information content estimation (odd)
+	IWSSIMParent     = true // include coefficient from parent (coarser) scale in neighborhood
+	IWSSIMSigmaNoise = 0.4  // variance of visual noise
+	iwssimTol        = 1e-15
+)
+
+// Pyramid 5-tap binomial filter with gain √2, as matlabPyrTools' namedFilter('binom5') used by reference implementation.
+var binom5 = []float64{math.Sqrt2 / 16, 4 * math.Sqrt2 / 16, 6 * math.Sqrt2 / 16, 4 * math.Sqrt2 / 16, math.Sqrt2 / 16}
+
+// Returns reflected (mirrored without edge) index i for length n, as in 'reflect1' edge handling of matlabPyrTools.
+func reflectIndex(i, n int) int {
+	if n == 1 {
+		return 0
+	}
+	for i < 0 || i >= n {
+		if i < 0 {
+			i = -i
+		}
+		if i >= n {
+			i = 2*(n-1) - i
+		}
+	}
+	return i
+}
+
+// Returns 2D correlation of img with separable kernel k (odd length), same size as img, using reflected edges.
+func filterReflect(img *FloatImage, k []float64) *FloatImage {
+	c := len(k) / 2
+
+	rows := NewFloatImage(img.W, img.H)
+	for y := 0; y < img.H; y++ {
+		for x := 0; x < img.W; x++ {
+			s := 0.0
+			for i, kv := range k {
+				s += kv * img.At(reflectIndex(x+i-c, img.W), y)
+			}
+			rows.Set(x, y, s)
+		}
+	}
+
+	res := NewFloatImage(img.W, img.H)
+	for y := 0; y < img.H; y++ {
+		for x := 0; x < img.W; x++ {
+			s := 0.0
+			for i, kv := range k {
+				s += kv * rows.At(x, reflectIndex(y+i-c, img.H))
+			}
+			res.Set(x, y, s)
+		}
+	}
+	return res
+}
+
+// Returns next (coarser) gaussian pyramid level of img, low-pass filtered by binom5 filter and downsampled by factor 2 (gain 2, as corrDn(...) in matlabPyrTools).
+func pyramidReduce(img *FloatImage) *FloatImage {
+	return decimate(filterReflect(img, binom5), 2)
+}
+
+// Returns img upsampled by factor 2 to size w x h, by inserting zeros and interpolating with binom5 filter (gain 1/2, as upConv(...) in matlabPyrTools).
+func pyramidExpand(img *FloatImage, w, h int) *FloatImage {
+	up := NewFloatImage(w, h)
+	for y := 0; y < img.H && 2*y < h; y++ {
+		for x := 0; x < img.W && 2*x < w; x++ {
+			up.Set(2*x, 2*y, img.At(x, y))
+		}
+	}
+	return filterReflect(up, binom5)
+}
+
+// Returns gaussian and laplacian pyramids of img with n scales, laplacian pyramid is equal to matlabPyrTools' buildLpyr(img, n) with binom5 filter.
+// Because of the filter gain, laplacian level s is scaled by 2^s compared to unit gain filter, which affects information content weights as in reference implementation.
+// Last laplacian level is equal to last gaussian level before normalization.
+// Returned gaussian levels are normalized to unit gain, so their values are in range of img values (as SSIM constants expect).
+func laplacianPyramid(img *FloatImage, n int) (gaussian, laplacian []*FloatImage) {
+	gaussian = []*FloatImage{img}
+	for s := 1; s < n; s++ {
+		gaussian = append(gaussian, pyramidReduce(gaussian[s-1]))
+	}
+
+	laplacian = make([]*FloatImage, n)
+	laplacian[n-1] = gaussian[n-1]
+	for s := 0; s < n-1; s++ {
+		g := gaussian[s]
+		laplacian[s] = mergeFloat(g, pyramidExpand(gaussian[s+1], g.W, g.H), func(va, vb float64) float64 { return va - vb })
+	}
+
+	for s := 1; s < n; s++ {
+		g, gain := NewFloatImage(gaussian[s].W, gaussian[s].H), math.Pow(2, float64(s))
+		for i, v := range gaussian[s].Pix {
+			g.Pix[i] = v / gain
+		}
+		gaussian[s] = g
+	}
+	return
+}
+
+// Pixel index with weight.
+type tap struct {
+	i int
+	w float64
+}
+
+// Returns taps of input pixels for every output pixel of 1D bilinear resize from n to m pixels, as MATLAB's imresize(..., 'bilinear') (edges mirrored).
+func bilinearTaps(n, m int) [][]tap {
+	scale := float64(m) / float64(n)
+	taps := make([][]tap, m)
+	for x := range taps {
+		u := float64(x+1)/scale + 0.5*(1-1/scale) // 1 based input coordinate
+		l := math.Floor(u)
+		taps[x] = []tap{{symmetricIndex(int(l)-1, n), 1 - (u - l)}, {symmetricIndex(int(l), n), u - l}}
+	}
+	return taps
+}
+
+// Returns taps of input pixels for every output pixel of 1D enlargement from n to 2n pixels, as imenlarge2(...) of reference implementation:
+// input is resized to 4n-3 pixels using bilinear interpolation, extended by linear extrapolation on both sides to 4n-1 pixels and every second pixel is taken.
+func enlarge2Taps(n int) [][]tap {
+	t1 := bilinearTaps(n, 4*n-3)
+	extrapolate := func(a, b []tap) []tap {
+		res := make([]tap, 0, len(a)+len(b))
+		for _, t := range a {
+			res = append(res, tap{t.i, 2 * t.w})
+		}
+		for _, t := range b {
+			res = append(res, tap{t.i, -t.w})
+		}
+		return res
+	}
+
+	taps := make([][]tap, 2*n)
+	taps[0] = extrapolate(t1[0], t1[1])
+	for k := 1; k < 2*n-1; k++ {
+		taps[k] = t1[2*k-1]
+	}
+	taps[2*n-1] = extrapolate(t1[4*n-4], t1[4*n-5])
+	return taps
+}
+
+// Returns img enlarged by factor 2 in both directions, as imenlarge2(...) of reference implementation (see enlarge2Taps). Used for parent coefficients in information content weighting.
+func enlarge2(img *FloatImage) *FloatImage {
+	xTaps, yTaps := enlarge2Taps(img.W), enlarge2Taps(img.H)
+
+	rows := NewFloatImage(2*img.W, img.H)
+	for y := 0; y < rows.H; y++ {
+		for x, taps := range xTaps {
+			s := 0.0
+			for _, t := range taps {
+				s += t.w * img.At(t.i, y)
+			}
+			rows.Set(x, y, s)
+		}
+	}
+
+	res := NewFloatImage(rows.W, 2*img.H)
+	for y, taps := range yTaps {
+		for x := 0; x < res.W; x++ {
+			s := 0.0
+			for _, t := range taps {
+				s += t.w * rows.At(x, t.i)
+			}
+			res.Set(x, y, s)
+		}
+	}
+	return res
+}
+
+// Returns eigenvalues and eigenvectors (columns of q) of symmetric matrix a using cyclic Jacobi method. Matrix a is not modified.
+func symmetricEigen(a [][]float64) (values []float64, q [][]float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	q = make([][]float64, n)
+	for i := range m {
+		m[i] = append([]float64(nil), a[i]...)
+		q[i] = make([]float64, n)
+		q[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += m[i][j] * m[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for r := p + 1; r < n; r++ {
+				if m[p][r] == 0 {
+					continue
+				}
+				theta := (m[r][r] - m[p][p]) / (2 * m[p][r])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					mkp, mkr := m[k][p], m[k][r]
+					m[k][p], m[k][r] = c*mkp-s*mkr, s*mkp+c*mkr
+				}
+				for k := 0; k < n; k++ {
+					mpk, mrk := m[p][k], m[r][k]
+					m[p][k], m[r][k] = c*mpk-s*mrk, s*mpk+c*mrk
+				}
+				for k := 0; k < n; k++ {
+					qkp, qkr := q[k][p], q[k][r]
+					q[k][p], q[k][r] = c*qkp-s*qkr, s*qkp+c*qkr
+				}
+			}
+		}
+	}
+
+	values = make([]float64, n)
+	for i := range values {
+		values[i] = m[i][i]
+	}
+	return
+}
+
+// Returns information content weight map of laplacian pyramid band s of reference (ro) and distorted (rd) pyramids.
+// Map contains only coefficients with whole neighborhood block inside band.
+func iwssimInfoContentWeights(ro, rd []*FloatImage, s int) *FloatImage {
+	imgo, imgd := ro[s], rd[s]
+	win := make([]float64, IWSSIMBlockSize)
+	for i := range win {
+		win[i] = 1 / float64(IWSSIMBlockSize)
+	}
+	mul := func(va, vb float64) float64 { return va * vb }
+
+	// Gain and additive noise variance of distortion channel.
+	meanX, meanY := filterValid(imgo, win), filterValid(imgd, win)
+	covXY := filterValid(mergeFloat(imgo, imgd, mul), win)
+	ssX := filterValid(mergeFloat(imgo, imgo, mul), win)
+	ssY := filterValid(mergeFloat(imgd, imgd, mul), win)
+	g, vv := NewFloatImage(meanX.W, meanX.H), NewFloatImage(meanX.W, meanX.H)
+	for i := range g.Pix {
+		mx, my := meanX.Pix[i], meanY.Pix[i]
+		cxy := covXY.Pix[i] - mx*my
+		sx, sy := math.Max(ssX.Pix[i]-mx*mx, 0), math.Max(ssY.Pix[i]-my*my, 0)
+
+		gv := cxy / (sx + iwssimTol)
+		vvv := sy - gv*cxy
+		if sx < iwssimTol {
+			gv, vvv = 0, sy
+		}
+		if sy < iwssimTol {
+			gv, vvv = 0, 0
+		}
+		g.Pix[i], vv.Pix[i] = gv, vvv
+	}
+
+	// Neighborhood vectors, spatial neighbors and optionally parent coefficient.
+	var parent *FloatImage
+	if IWSSIMParent && s < len(ro)-2 {
+		parent = enlarge2(ro[s+1])
+	}
+	lb := (IWSSIMBlockSize - 1) / 2
+	n := IWSSIMBlockSize * IWSSIMBlockSize
+	if parent != nil {
+		n++
+	}
+	vector := func(x, y int, v []float64) {
+		i := 0
+		for dy := -lb; dy <= lb; dy++ {
+			for dx := -lb; dx <= lb; dx++ {
+				v[i] = imgo.At(x+dx, y+dy)
+				i++
+			}
+		}
+		if parent != nil {
+			v[i] = parent.At(x, y)
+		}
+	}
+
+	// Covariance matrix of neighborhood vectors.
+	cu := make([][]float64, n)
+	for i := range cu {
+		cu[i] = make([]float64, n)
+	}
+	v := make([]float64, n)
+	for y := lb; y < imgo.H-lb; y++ {
+		for x := lb; x < imgo.W-lb; x++ {
+			vector(x, y, v)
+			for i := range v {
+				for j := range v {
+					cu[i][j] += v[i] * v[j]
+				}
+			}
+		}
+	}
+	nexp := float64(g.W * g.H)
+	for i := range cu {
+		for j := range cu[i] {
+			cu[i][j] /= nexp
+		}
+	}
+
+	// Force positive semi-definite covariance, keeping sum of eigenvalues.
+	l, q := symmetricEigen(cu)
+	sum, sumPos := 0.0, 0.0
+	for _, lv := range l {
+		sum += lv
+		if lv > 0 {
+			sumPos += lv
+		}
+	}
+	if sumPos == 0 {
+		sumPos = 1
+	}
+	for i, lv := range l {
+		l[i] = math.Max(lv, 0) * sum / sumPos
+	}
+
+	// Inverse of covariance matrix.
+	cuInv := make([][]float64, n)
+	for i := range cuInv {
+		cuInv[i] = make([]float64, n)
+		for j := range cuInv[i] {
+			for k, lv := range l {
+				cuInv[i][j] += q[i][k] * q[j][k] / lv
+			}
+		}
+	}
+
+	sn := IWSSIMSigmaNoise
+	res := NewFloatImage(g.W, g.H)
+	for y := 0; y < res.H; y++ {
+		for x := 0; x < res.W; x++ {
+			vector(x+lb, y+lb, v)
+			ss := 0.0
+			for i := range v {
+				for j := range v {
+					ss += v[i] * cuInv[i][j] * v[j]
+				}
+			}
+			ss /= float64(n)
+
+			gv, vvv := g.At(x, y), vv.At(x, y)
+			infow := 0.0
+			for _, lv := range l {
+				infow += math.Log2(1 + ((vvv+(1+gv*gv)*sn)*ss*lv+sn*vvv)/(sn*sn))
+			}
+			if infow < iwssimTol {
+				infow = 0
+			}
+			res.Set(x, y, infow)
+		}
+	}
+	return res
+}
+
+// IWSSIM returns information content weighted multi-scale structural similarity index of the two input color images, converted to gray images using gray8(...).
+// Contrast-structure maps are computed on gaussian pyramid scales and pooled using information content weights computed from laplacian pyramid, scales are combined using MSSSIMWeights.
+func IWSSIM(a, b image.Image) float64 {
+	return must(IWSSIMErr(a, b))
+}
+
+// IWSSIMErr is IWSSIM(...) returning ErrBoundsMismatch or ErrImageTooSmall (if coarsest scale is smaller than window) instead of panic.
+func IWSSIMErr(a, b image.Image) (float64, error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, err
+	}
+
+	gaussO, lapO := laplacianPyramid(grayFloat(gray8(a)), IWSSIMScales)
+	gaussD, lapD := laplacianPyramid(grayFloat(gray8(b)), IWSSIMScales)
+
+	// Difference between sizes of contrast-structure maps and information content weight maps.
+	bound := (SSIMWindowSize-1)/2 - (IWSSIMBlockSize-1)/2
+
+	res := 1.0
+	for s := 0; s < IWSSIMScales; s++ {
+		if gaussO[s].W < SSIMWindowSize || gaussO[s].H < SSIMWindowSize {
+			return 0, ErrImageTooSmall
+		}
+		ssimMap, csMap := ssimWindowed(gaussO[s], gaussD[s])
+		if s == IWSSIMScales-1 {
+			res *= math.Pow(ssimMap.Mean(), MSSSIMWeights[s])
+			break
+		}
+
+		iw := iwssimInfoContentWeights(lapO, lapD, s)
+		csSum, iwSum := 0.0, 0.0
+		for y := 0; y < csMap.H; y++ {
+			for x := 0; x < csMap.W; x++ {
+				w := iw.At(x+bound, y+bound)
+				csSum += csMap.At(x, y) * w
+				iwSum += w
+			}
+		}
+		res *= math.Pow(csSum/iwSum, MSSSIMWeights[s])
+	}
+	return res, nil
+}
+
+func init() {
+	parent := 0.0
+	if IWSSIMParent {
+		parent = 1
+	}
+	Register(Info{
+		Name:      "IWSSIM",
+		Aliases:   []string{"IW-SSIM"},
+		Direction: HigherIsBetter,
+		Min:       0,
+		Max:       1,
+		Color:     GrayImages,
+		Config:    map[string]float64{"scales": IWSSIMScales, "blockSize": IWSSIMBlockSize, "parent": parent, "sigmaNoise": IWSSIMSigmaNoise},
+		Version:   2,
+		Metric:    MetricFunc(IWSSIMErr),
+	})
+}
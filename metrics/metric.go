@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"errors"
+	"image"
+)
+
+// Errors returned by metrics.
+var (
+	ErrBoundsMismatch = errors.New("images have to have equal bounds")
+	ErrImageTooSmall  = errors.New("images are too small for metric")
+	ErrFlatReference  = errors.New("reference image has no variance")
+)
+
+// Metric is a full-reference image quality metric, comparing distorted image b against reference image a.
+type Metric interface {
+	Compute(a, b image.Image) (float64, error)
+}
+
+// MetricFunc is an adapter to allow the use of ordinary functions as metrics.
+type MetricFunc func(a, b image.Image) (float64, error)
+
+// Compute returns f(a, b).
+func (f MetricFunc) Compute(a, b image.Image) (float64, error) {
+	return f(a, b)
+}
+
+// Returns ErrBoundsMismatch if images a, b have different bounds.
+func checkBounds(a, b image.Image) error {
+	if !a.Bounds().Eq(b.Bounds()) {
+		return ErrBoundsMismatch
+	}
+	return nil
+}
+
+// Returns v if err is nil, panics otherwise. Used by convenience wrappers of error returning metrics.
+func must(v float64, err error) float64 {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Returns img if err is nil, panics otherwise. Used by convenience wrappers of error returning functions producing float images.
+func mustImage(img *FloatImage, err error) *FloatImage {
+	if err != nil {
+		panic(err)
+	}
+	return img
+}
@@ -0,0 +1,360 @@
+// Package metrics implements full-reference image quality assessment (IQA) metrics.
+package metrics
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/jezek/goMDID/stats"
+)
+
+// Returns gray image using default go (luminance) method to convert from rgb.
+func gray8(img image.Image) *image.Gray {
+	grayImg := image.NewGray(img.Bounds())
+
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			grayImg.Set(x, y, img.At(x, y))
+			//continue
+
+			//R, G, B, _ := img.At(x, y).RGBA()
+			// Luminance: Y = 0.299R + 0.587G + 0.114B
+			//Y := 0.299*float64(R) + 0.587*float64(G) + 0.1148*float64(B)
+			// Mean: Y = (R + G + B) / 3
+			//Y := (float64(R) + float64(G) + float64(B)) / 3.0
+			// Luma: Y = 0.2126R + 0.7152G + 0.0722B
+			//Y := 0.2126*float64(R) + 0.7152*float64(G) + 0.0722*float64(B)
+			// Luster: Y = (min(R, G, B) + max(R, G, B))/2
+			//grayImg.Set(x, y, color.Gray{uint8(Y / 257)})
+		}
+	}
+
+	return grayImg
+}
+
+// Returns Mean-Squared Error of the two input 8-bit grayscale images.
+// Using: http://homepages.inf.ed.ac.uk/rbf/CVonline/LOCAL_COPIES/VELDHUIZEN/node18.html
+func MSEGray(a, b *image.Gray) float64 {
+	return must(MSEGrayErr(a, b))
+}
+
+// MSEGrayErr returns Mean-Squared Error of the two input 8-bit grayscale images or ErrBoundsMismatch.
+func MSEGrayErr(a, b *image.Gray) (float64, error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, err
+	}
+
+	values := make([]float64, a.Bounds().Dx()*a.Bounds().Dy())
+	for i := range values {
+		x, y := i%a.Bounds().Dx(), i/a.Bounds().Dx()
+		values[i] = float64(int(b.GrayAt(x, y).Y) - int(a.GrayAt(x, y).Y)) // error
+		values[i] *= values[i]                                             // square error
+	}
+	return stats.MeanA(values), nil // mean square error
+}
+
+// Returns Mean-Squared Error of the two input color images, converting to gray images (using gray8(...)) and then computing MSEGray(...)
+// Using: http://homepages.inf.ed.ac.uk/rbf/CVonline/LOCAL_COPIES/VELDHUIZEN/node18.html
+func MSE(a, b image.Image) float64 {
+	return must(MSEErr(a, b))
+}
+
+// MSEErr is MSE(...) returning ErrBoundsMismatch instead of panic.
+func MSEErr(a, b image.Image) (float64, error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, err
+	}
+
+	return MSEGrayErr(gray8(a), gray8(b))
+
+}
+
+// Returns Peak Signal-to-Noise Ratio of the two input color images, using MSE(...) in calculation.
+// Using: http://homepages.inf.ed.ac.uk/rbf/CVonline/LOCAL_COPIES/VELDHUIZEN/node18.html
+func PSNR(i1, i2 image.Image) float64 {
+	return must(PSNRErr(i1, i2))
+}
+
+// PSNRErr is PSNR(...) returning ErrBoundsMismatch instead of panic.
+func PSNRErr(i1, i2 image.Image) (float64, error) {
+	mse, err := MSEErr(i1, i2)
+	if err != nil {
+		return 0, err
+	}
+	return -10 * math.Log10(mse/65025.0), nil // 65025 = 255*255
+}
+
+func vector(img *image.Gray) []float64 {
+	res := make([]float64, img.Bounds().Dx()*img.Bounds().Dy())
+	for i := range res {
+		x, y := i%img.Bounds().Dx(), i/img.Bounds().Dx()
+		res[i] = float64(img.GrayAt(x, y).Y)
+	}
+	return res
+}
+
+func merge(a, b []float64, f func(i int) float64) ([]float64, error) {
+	if len(a) != len(b) {
+		return nil, stats.ErrLengthMismatch
+	}
+	res := make([]float64, len(a))
+	for i := range res {
+		res[i] = f(i)
+	}
+	return res, nil
+}
+
+// Returns an gray image for every color component of input image.
+func rgbExplodeToGray8(img image.Image) (R *image.Gray, G *image.Gray, B *image.Gray) {
+	R, G, B = image.NewGray(img.Bounds()), image.NewGray(img.Bounds()), image.NewGray(img.Bounds())
+
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA() //uint16 represented as uint32
+			R.Set(x, y, color.Gray{uint8(r / 257)})
+			G.Set(x, y, color.Gray{uint8(g / 257)})
+			B.Set(x, y, color.Gray{uint8(b / 257)})
+		}
+	}
+
+	return
+}
+
+// Returns Mean-Squared Error of the two input color images, by decompositing RGB values to separate images and return average of them.
+func MSErgb(a, b image.Image) float64 {
+	return must(MSErgbErr(a, b))
+}
+
+// MSErgbErr is MSErgb(...) returning ErrBoundsMismatch instead of panic.
+func MSErgbErr(a, b image.Image) (float64, error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, err
+	}
+
+	aR, aG, aB := rgbExplodeToGray8(a)
+	bR, bG, bB := rgbExplodeToGray8(b)
+	mseR, mseG, mseB := MSEGray(aR, bR), MSEGray(aG, bG), MSEGray(aB, bB)
+
+	return (mseR + mseG + mseB) / 3, nil
+}
+
+// Returns Peak Signal-to-Noise Ratio of the two input color images using MSErgb(...).
+// Using: http://homepages.inf.ed.ac.uk/rbf/CVonline/LOCAL_COPIES/VELDHUIZEN/node18.html
+func PSNRrgb(i1, i2 image.Image) float64 {
+	return must(PSNRrgbErr(i1, i2))
+}
+
+// PSNRrgbErr is PSNRrgb(...) returning ErrBoundsMismatch instead of panic.
+func PSNRrgbErr(i1, i2 image.Image) (float64, error) {
+	mse, err := MSErgbErr(i1, i2)
+	if err != nil {
+		return 0, err
+	}
+	return -10 * math.Log10(mse/65025.0), nil // 65025 = 255*255
+}
+
+// SSIM (Structural SIMilarity) index.
+// Using: Z. Wang, A. C. Bovik, H. R. Sheikh and E. P. Simoncelli, "Image quality assessment: From error visibility to structural similarity," IEEE Transactions on Image Processing, vol. 13, no. 4, pp. 600-612, Apr. 2004.
+// Reference implementation: https://ece.uwaterloo.ca/~z70wang/research/ssim/
+
+// Default SSIM constants.
+const (
+	L  = 255.0
+	K1 = 0.01
+	K2 = 0.03
+
+	SSIMWindowSize  = 11  // gaussian window size
+	SSIMWindowSigma = 1.5 // gaussian window standart deviation
+)
+
+// Calculated SSIM coeficients.
+var (
+	C1 = math.Pow((K1 * L), 2.0)
+	C2 = math.Pow((K2 * L), 2.0)
+)
+
+// Returns normalized 1D gaussian kernel. Outer product of two such kernels is equal to MATLAB's fspecial('gaussian', size, sigma).
+func gaussianKernel(size int, sigma float64) []float64 {
+	k := make([]float64, size)
+	c := float64(size-1) / 2
+	for i := range k {
+		d := float64(i) - c
+		k[i] = math.Exp(-(d * d) / (2 * sigma * sigma))
+	}
+	sum := stats.Sum(k)
+	for i := range k {
+		k[i] /= sum
+	}
+	return k
+}
+
+// Returns SSIM downsampling factor for image of size w x h, as in reference implementation (image is downsampled to approx. 256 pixels on smaller side).
+func ssimDownsampleFactor(w, h int) int {
+	m := w
+	if h < m {
+		m = h
+	}
+	f := int(math.Round(float64(m) / 256))
+	if f < 1 {
+		return 1
+	}
+	return f
+}
+
+// Returns local gaussian window weighted means, variances and covariance of float images a, b.
+func ssimLocalStats(a, b *FloatImage) (mu1, mu2, sigma1Sq, sigma2Sq, sigma12 *FloatImage) {
+	win := gaussianKernel(SSIMWindowSize, SSIMWindowSigma)
+	mul := func(va, vb float64) float64 { return va * vb }
+
+	mu1, mu2 = filterValid(a, win), filterValid(b, win)
+	sigma1Sq = filterValid(mergeFloat(a, a, mul), win)
+	sigma2Sq = filterValid(mergeFloat(b, b, mul), win)
+	sigma12 = filterValid(mergeFloat(a, b, mul), win)
+	for i := range mu1.Pix {
+		m1, m2 := mu1.Pix[i], mu2.Pix[i]
+		sigma1Sq.Pix[i] -= m1 * m1
+		sigma2Sq.Pix[i] -= m2 * m2
+		sigma12.Pix[i] -= m1 * m2
+	}
+	return
+}
+
+// Returns SSIM map and contrast-structure map of float images a, b, computed with gaussian window (without downsampling).
+func ssimWindowed(a, b *FloatImage) (ssimMap, csMap *FloatImage) {
+	return ssimFromLocalStats(ssimLocalStats(a, b))
+}
+
+// Returns SSIM map and contrast-structure map from local statistics returned by ssimLocalStats(...).
+func ssimFromLocalStats(mu1, mu2, sigma1Sq, sigma2Sq, sigma12 *FloatImage) (ssimMap, csMap *FloatImage) {
+	ssimMap, csMap = NewFloatImage(mu1.W, mu1.H), NewFloatImage(mu1.W, mu1.H)
+	for i := range ssimMap.Pix {
+		m1, m2 := mu1.Pix[i], mu2.Pix[i]
+		cs := (2*sigma12.Pix[i] + C2) / (sigma1Sq.Pix[i] + sigma2Sq.Pix[i] + C2)
+		csMap.Pix[i] = cs
+		ssimMap.Pix[i] = (2*m1*m2 + C1) / (m1*m1 + m2*m2 + C1) * cs
+	}
+	return
+}
+
+// SSIM returns mean structural similarity index of the two input color images, converted to gray images using gray8(...).
+// Images are automatically downsampled and then compared using 11x11 gaussian window with standart deviation 1.5, as in reference implementation.
+func SSIM(a, b image.Image) float64 {
+	return must(SSIMErr(a, b))
+}
+
+// SSIMErr is SSIM(...) returning ErrBoundsMismatch or ErrImageTooSmall (if downsampled images are smaller than window) instead of panic.
+func SSIMErr(a, b image.Image) (float64, error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, err
+	}
+
+	f := ssimDownsampleFactor(a.Bounds().Dx(), a.Bounds().Dy())
+	fa, fb := averageDownsample(grayFloat(gray8(a)), f, symmetricPadding), averageDownsample(grayFloat(gray8(b)), f, symmetricPadding)
+	if fa.W < SSIMWindowSize || fa.H < SSIMWindowSize {
+		return 0, ErrImageTooSmall
+	}
+
+	ssimMap, _ := ssimWindowed(fa, fb)
+	return ssimMap.Mean(), nil
+}
+
+// SSIMMaps holds per pixel SSIM quality map and its luminance, contrast and structure component maps.
+// Maps are computed on downsampled images (see SSIM) and contain only pixels where the whole gaussian window fits the image.
+type SSIMMaps struct {
+	SSIM      *FloatImage
+	Luminance *FloatImage
+	Contrast  *FloatImage
+	Structure *FloatImage
+}
+
+// SSIMWithMaps returns mean structural similarity index (same as SSIM(...)) of the two input color images and the maps the index was pooled from.
+// Component maps use C3 = C2/2, so SSIM map equals to product of luminance, contrast and structure maps.
+func SSIMWithMaps(a, b image.Image) (float64, SSIMMaps) {
+	res, maps, err := SSIMWithMapsErr(a, b)
+	return must(res, err), maps
+}
+
+// SSIMWithMapsErr is SSIMWithMaps(...) returning errors as SSIMErr(...) instead of panic.
+func SSIMWithMapsErr(a, b image.Image) (float64, SSIMMaps, error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, SSIMMaps{}, err
+	}
+
+	f := ssimDownsampleFactor(a.Bounds().Dx(), a.Bounds().Dy())
+	fa, fb := averageDownsample(grayFloat(gray8(a)), f, symmetricPadding), averageDownsample(grayFloat(gray8(b)), f, symmetricPadding)
+	if fa.W < SSIMWindowSize || fa.H < SSIMWindowSize {
+		return 0, SSIMMaps{}, ErrImageTooSmall
+	}
+
+	mu1, mu2, sigma1Sq, sigma2Sq, sigma12 := ssimLocalStats(fa, fb)
+	ssimMap, _ := ssimFromLocalStats(mu1, mu2, sigma1Sq, sigma2Sq, sigma12)
+	maps := SSIMMaps{
+		SSIM:      ssimMap,
+		Luminance: NewFloatImage(mu1.W, mu1.H),
+		Contrast:  NewFloatImage(mu1.W, mu1.H),
+		Structure: NewFloatImage(mu1.W, mu1.H),
+	}
+	C3 := C2 / 2
+	for i := range mu1.Pix {
+		m1, m2 := mu1.Pix[i], mu2.Pix[i]
+		// Variances can be slightly negative due to floating point errors.
+		sd1, sd2 := math.Sqrt(math.Max(sigma1Sq.Pix[i], 0)), math.Sqrt(math.Max(sigma2Sq.Pix[i], 0))
+		maps.Luminance.Pix[i] = (2*m1*m2 + C1) / (m1*m1 + m2*m2 + C1)
+		maps.Contrast.Pix[i] = (2*sd1*sd2 + C2) / (sigma1Sq.Pix[i] + sigma2Sq.Pix[i] + C2)
+		maps.Structure.Pix[i] = (sigma12.Pix[i] + C3) / (sd1*sd2 + C3)
+	}
+	return ssimMap.Mean(), maps, nil
+}
+
+// MS-SSIM (Multi-Scale Structural SIMilarity) index.
+// Using: Z. Wang, E. P. Simoncelli and A. C. Bovik, "Multi-scale structural similarity for image quality assessment," Invited Paper, IEEE Asilomar Conference on Signals, Systems and Computers, Nov. 2003.
+// Reference implementation: https://ece.uwaterloo.ca/~z70wang/research/iwssim/ (msssim.m)
+
+// MSSSIMWeights are exponents of contrast-structure (and luminance at last scale) components for every scale, from finest to coarsest.
+var MSSSIMWeights = []float64{0.0448, 0.2856, 0.3001, 0.2363, 0.1333}
+
+// MSSSIM returns multi-scale structural similarity index of the two input color images, converted to gray images using gray8(...).
+// Images are compared on len(MSSSIMWeights) scales, every next scale is low-pass filtered by 2x2 average filter and downsampled by factor 2.
+func MSSSIM(a, b image.Image) float64 {
+	return must(MSSSIMErr(a, b))
+}
+
+// MSSSIMErr is MSSSIM(...) returning ErrBoundsMismatch or ErrImageTooSmall (if coarsest scale is smaller than window) instead of panic.
+// Negative mean contrast-structure (or SSIM at coarsest scale) is clamped to 0, so the index is 0 for such images (reference implementation returns complex number).
+func MSSSIMErr(a, b image.Image) (float64, error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, err
+	}
+
+	fa, fb := grayFloat(gray8(a)), grayFloat(gray8(b))
+
+	res := 1.0
+	for l, w := range MSSSIMWeights {
+		if fa.W < SSIMWindowSize || fa.H < SSIMWindowSize {
+			return 0, ErrImageTooSmall
+		}
+		ssimMap, csMap := ssimWindowed(fa, fb)
+		if l == len(MSSSIMWeights)-1 {
+			res *= math.Pow(math.Max(ssimMap.Mean(), 0), w)
+			break
+		}
+		res *= math.Pow(math.Max(csMap.Mean(), 0), w)
+		fa, fb = averageDownsample(fa, 2, symmetricPadding), averageDownsample(fb, 2, symmetricPadding)
+	}
+	return res, nil
+}
+
+func init() {
+	ssimConfig := map[string]float64{"L": L, "K1": K1, "K2": K2, "window": SSIMWindowSize, "sigma": SSIMWindowSigma}
+	Register(Info{Name: "MSEg", Version: 1, Direction: LowerIsBetter, Min: 0, Max: 65025, Color: GrayImages, Metric: MetricFunc(MSEErr)})
+	Register(Info{Name: "PSNRg", Version: 1, Direction: HigherIsBetter, Min: 0, Max: math.Inf(1), Color: GrayImages, Metric: MetricFunc(PSNRErr)})
+	Register(Info{Name: "MSE", Aliases: []string{"MSErgb"}, Version: 1, Direction: LowerIsBetter, Min: 0, Max: 65025, Color: ColorImages, Metric: MetricFunc(MSErgbErr)})
+	Register(Info{Name: "PSNR", Aliases: []string{"PSNRrgb"}, Version: 1, Direction: HigherIsBetter, Min: 0, Max: math.Inf(1), Color: ColorImages, Metric: MetricFunc(PSNRrgbErr)})
+	Register(Info{Name: "SSIM", Version: 1, Direction: HigherIsBetter, Min: -1, Max: 1, Color: GrayImages, Config: ssimConfig, Metric: MetricFunc(SSIMErr)})
+	msssimConfig := map[string]float64{"scales": float64(len(MSSSIMWeights))}
+	for k, v := range ssimConfig {
+		msssimConfig[k] = v
+	}
+	Register(Info{Name: "MSSSIM", Aliases: []string{"MS-SSIM"}, Version: 2, Direction: HigherIsBetter, Min: 0, Max: 1, Color: GrayImages, Config: msssimConfig, Metric: MetricFunc(MSSSIMErr)})
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Direction tells whether higher or lower metric values mean better quality.
+type Direction int
+
+const (
+	HigherIsBetter Direction = iota
+	LowerIsBetter
+)
+
+func (d Direction) String() string {
+	switch d {
+	case HigherIsBetter:
+		return "higher-is-better"
+	case LowerIsBetter:
+		return "lower-is-better"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// ColorRequirement tells which information of color images metric uses.
+type ColorRequirement int
+
+const (
+	GrayImages  ColorRequirement = iota // images are converted to 8-bit gray images (luminance only) before computation
+	ColorImages                         // RGB channels are used by metric (e.g. for own luminance conversion or chrominance)
+)
+
+func (c ColorRequirement) String() string {
+	switch c {
+	case GrayImages:
+		return "gray"
+	case ColorImages:
+		return "color"
+	}
+	return fmt.Sprintf("ColorRequirement(%d)", int(c))
+}
+
+// Info describes registered metric.
+type Info struct {
+	Name      string
+	Aliases   []string
+	Direction Direction
+	// Range of metric values, bounds can be infinite.
+	Min, Max float64
+	Color    ColorRequirement
+	// Configuration (constants) the metric is computed with, parameter name => value.
+	// It is descriptive only (e.g. for reports), metric does not read it, so changing it does not change results.
+	Config map[string]float64
+	// Version of implementation, starting with 1. It has to be increased with every change of implementation or its constants, which changes results,
+	// so values computed by previous implementation and cached by version are recomputed.
+	Version int
+	Metric  Metric
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Info{} // registered metrics by names and aliases
+)
+
+// Register makes metric described by info available by its name and aliases. Info is copied, later changes of its aliases or config do not affect registry.
+// Panics if info has no name, metric or version, or if name or some of aliases is already registered.
+func Register(info Info) {
+	if info.Name == "" || info.Metric == nil {
+		panic("metric info without name or metric")
+	}
+	if info.Version < 1 {
+		panic("metric " + info.Name + " registered without version")
+	}
+	info = info.clone()
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, n := range append([]string{info.Name}, info.Aliases...) {
+		if _, ok := registry[n]; ok {
+			panic("metric " + n + " already registered")
+		}
+	}
+	for _, n := range append([]string{info.Name}, info.Aliases...) {
+		registry[n] = &info
+	}
+}
+
+// Lookup returns info of metric registered by name or alias.
+func Lookup(name string) (Info, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	info, ok := registry[name]
+	if !ok {
+		return Info{}, false
+	}
+	return info.clone(), true
+}
+
+// Registered returns infos of all registered metrics, sorted by name.
+func Registered() []Info {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	res := []Info{}
+	for n, info := range registry {
+		if n == info.Name {
+			res = append(res, info.clone())
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// Returns copy of info, which does not share aliases and config with info.
+func (i Info) clone() Info {
+	if i.Aliases != nil {
+		i.Aliases = append([]string{}, i.Aliases...)
+	}
+	if i.Config != nil {
+		config := make(map[string]float64, len(i.Config))
+		for k, v := range i.Config {
+			config[k] = v
+		}
+		i.Config = config
+	}
+	return i
+}
+
+// ConfigString returns configuration of metric as sorted list of parameters (e.g. "K1=0.01,K2=0.03").
+func (i Info) ConfigString() string {
+	params := make([]string, 0, len(i.Config))
+	for k, v := range i.Config {
+		params = append(params, fmt.Sprintf("%s=%g", k, v))
+	}
+	sort.Strings(params)
+	return strings.Join(params, ",")
+}
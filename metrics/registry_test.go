@@ -0,0 +1,68 @@
+package metrics
+
+import "testing"
+
+func TestRegisteredVersions(t *testing.T) {
+	infos := Registered()
+	if len(infos) == 0 {
+		t.Fatal("no registered metrics")
+	}
+	for _, info := range infos {
+		if info.Version < 1 {
+			t.Errorf("%s registered with version %d", info.Name, info.Version)
+		}
+	}
+}
+
+func TestConfigString(t *testing.T) {
+	info, _ := Lookup("SSIM")
+	if c, want := info.ConfigString(), "K1=0.01,K2=0.03,L=255,sigma=1.5,window=11"; c != want {
+		t.Errorf("SSIM config %q, want %q", c, want)
+	}
+}
+
+func TestLookupReturnsCopy(t *testing.T) {
+	info, ok := Lookup("MS-SSIM")
+	if !ok || info.Name != "MSSSIM" {
+		t.Fatalf("Lookup(MS-SSIM) = %v, %v, want MSSSIM info", info.Name, ok)
+	}
+	config := info.ConfigString()
+	info.Config["K1"] = 1
+	info.Aliases[0] = "changed"
+	for _, info := range Registered() {
+		if info.Config != nil {
+			info.Config["K2"] = 1
+		}
+	}
+
+	info, _ = Lookup("MSSSIM")
+	if info.ConfigString() != config {
+		t.Errorf("config changed by modifying returned infos: %q, was %q", info.ConfigString(), config)
+	}
+	if info.Aliases[0] != "MS-SSIM" {
+		t.Errorf("alias changed by modifying returned info: %q", info.Aliases[0])
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		info Info
+	}{
+		{"without name", Info{Version: 1, Metric: MetricFunc(SSIMErr)}},
+		{"without version", Info{Name: "testNoVersion", Metric: MetricFunc(SSIMErr)}},
+		{"duplicate alias", Info{Name: "testDuplicate", Aliases: []string{"MS-SSIM"}, Version: 1, Metric: MetricFunc(SSIMErr)}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register %s did not panic", c.name)
+				}
+			}()
+			Register(c.info)
+		}()
+	}
+	if _, ok := Lookup("testDuplicate"); ok {
+		t.Errorf("metric with duplicate alias registered")
+	}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+// WriteSSIMMaps computes SSIM maps (see SSIMWithMaps) of distorted image b against reference image a and writes them as grayscale PNG heatmaps to dir.
+// Maps are written to files <name>_<map>.png, where black pixel is the lowest possible value of the map (-1 for SSIM and structure, 0 for luminance and contrast) and white pixel is 1.
+func WriteSSIMMaps(a, b image.Image, dir, name string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating directory error: %w", err)
+	}
+
+	_, maps, err := SSIMWithMapsErr(a, b)
+	if err != nil {
+		return fmt.Errorf("computing SSIM maps error: %w", err)
+	}
+	for _, m := range []struct {
+		name     string
+		img      *FloatImage
+		min, max float64
+	}{
+		{"ssim", maps.SSIM, -1, 1},
+		{"luminance", maps.Luminance, 0, 1},
+		{"contrast", maps.Contrast, 0, 1},
+		{"structure", maps.Structure, -1, 1},
+	} {
+		if err := writePNG(filepath.Join(dir, name+"_"+m.name+".png"), m.img, m.min, m.max); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes float image to PNG file on path, mapping values from min-max range to gray levels.
+func writePNG(path string, img *FloatImage, min, max float64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating file error: %w", err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img.Gray(min, max)); err != nil {
+		return fmt.Errorf("encoding png error: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"image"
+	"math"
+)
+
+// VIF (Visual Information Fidelity) index, pixel domain version (VIFp).
+// Using: H. R. Sheikh and A. C. Bovik, "Image information and visual quality," IEEE Transactions on Image Processing, vol. 15, no. 2, pp. 430-444, Feb. 2006.
+// Reference implementation: https://live.ece.utexas.edu/research/Quality/VIF.htm (vifp_mscale.m)
+
+// Default VIFp constants.
+const (
+	VIFpScales     = 4   // number of scales
+	VIFpSigmaNoise = 2.0 // variance of HVS noise
+	vifpEps        = 1e-10
+)
+
+// VIFp returns pixel domain visual information fidelity of distorted image b against reference image a, both converted to gray images using gray8(...).
+// Higher value means better quality, 1 for identical images.
+func VIFp(a, b image.Image) float64 {
+	return must(VIFpErr(a, b))
+}
+
+// VIFpErr is VIFp(...) returning ErrBoundsMismatch, ErrImageTooSmall (if some scale is smaller than its window) or ErrFlatReference (if reference image carries no information, so VIFp is 0/0) instead of panic.
+func VIFpErr(a, b image.Image) (float64, error) {
+	if err := checkBounds(a, b); err != nil {
+		return 0, err
+	}
+
+	ref, dis := grayFloat(gray8(a)), grayFloat(gray8(b))
+	mul := func(va, vb float64) float64 { return va * vb }
+
+	num, den := 0.0, 0.0
+	for scale := 1; scale <= VIFpScales; scale++ {
+		n := 1<<(VIFpScales-scale+1) + 1
+		win := gaussianKernel(n, float64(n)/5)
+
+		if scale > 1 {
+			ref, dis = decimate(filterValid(ref, win), 2), decimate(filterValid(dis, win), 2)
+		}
+
+		mu1, mu2 := filterValid(ref, win), filterValid(dis, win)
+		if len(mu1.Pix) == 0 {
+			return 0, ErrImageTooSmall
+		}
+		s11 := filterValid(mergeFloat(ref, ref, mul), win)
+		s22 := filterValid(mergeFloat(dis, dis, mul), win)
+		s12 := filterValid(mergeFloat(ref, dis, mul), win)
+
+		for i := range mu1.Pix {
+			m1, m2 := mu1.Pix[i], mu2.Pix[i]
+			sigma1Sq := math.Max(s11.Pix[i]-m1*m1, 0)
+			sigma2Sq := math.Max(s22.Pix[i]-m2*m2, 0)
+			sigma12 := s12.Pix[i] - m1*m2
+
+			// Gain and additive noise variance of distortion channel.
+			g := sigma12 / (sigma1Sq + vifpEps)
+			svSq := sigma2Sq - g*sigma12
+			if sigma1Sq < vifpEps {
+				g, svSq, sigma1Sq = 0, sigma2Sq, 0
+			}
+			if sigma2Sq < vifpEps {
+				g, svSq = 0, 0
+			}
+			if g < 0 {
+				g, svSq = 0, sigma2Sq
+			}
+			if svSq <= vifpEps {
+				svSq = vifpEps
+			}
+
+			num += math.Log10(1 + g*g*sigma1Sq/(svSq+VIFpSigmaNoise))
+			den += math.Log10(1 + sigma1Sq/VIFpSigmaNoise)
+		}
+	}
+	if den == 0 {
+		return 0, ErrFlatReference
+	}
+	return num / den, nil
+}
+
+func init() {
+	Register(Info{
+		Name:      "VIFp",
+		Aliases:   []string{"VIFP"},
+		Direction: HigherIsBetter,
+		Min:       0,
+		Max:       math.Inf(1),
+		Color:     GrayImages,
+		Config:    map[string]float64{"scales": VIFpScales, "sigmaNoise": VIFpSigmaNoise},
+		Version:   2,
+		Metric:    MetricFunc(VIFpErr),
+	})
+}
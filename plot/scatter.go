@@ -0,0 +1,214 @@
+// Package plot implements pure Go SVG scatter plots of objective metric scores against subjective scores.
+package plot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync/atomic"
+)
+
+// Default plot size in pixels.
+const (
+	DefaultWidth  = 640
+	DefaultHeight = 480
+)
+
+// Plot margins in pixels (left margin contains y axis labels, right margin contains legend).
+const (
+	marginLeft   = 70
+	marginRight  = 170
+	marginTop    = 40
+	marginBottom = 55
+)
+
+// Palette of point colors, colors for more groups are generated.
+var Palette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+// Counter of written plots, used for unique element ids of plots embedded in one document.
+var plotCounter int64
+
+// Point is a scatter plot point, colored by its group.
+type Point struct {
+	X, Y  float64
+	Group string
+}
+
+// Scatter is a scatter plot of points with optional curve drawn on top.
+type Scatter struct {
+	Title, XLabel, YLabel string
+	Points                []Point
+	// Curve drawn over points range, if not nil.
+	Curve      func(x float64) float64
+	CurveLabel string
+	// Plot size in pixels, DefaultWidth and DefaultHeight if zero.
+	Width, Height int
+}
+
+// Returns color for group index i of n groups.
+func groupColor(i, n int) string {
+	if n <= len(Palette) {
+		return Palette[i]
+	}
+	// Evenly spaced hues with alternating lightness.
+	return fmt.Sprintf("hsl(%d, 70%%, %d%%)", i*360/n, 40+10*(i%2))
+}
+
+// Returns ticks with "nice" step (1, 2 or 5 times power of 10) covering range min-max, approximately n ticks.
+func niceTicks(min, max float64, n int) []float64 {
+	if max <= min {
+		return []float64{min}
+	}
+	raw := (max - min) / float64(n)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*mag >= raw {
+			step = m * mag
+			break
+		}
+	}
+	res := []float64{}
+	for v := math.Ceil(min/step) * step; v <= max+step*1e-9; v += step {
+		// Avoid printing -0 and accumulated rounding errors.
+		res = append(res, math.Round(v/step)*step+0)
+	}
+	return res
+}
+
+// Returns range of values padded by 5% on both sides, (0, 1) if there are no values.
+func paddedRange(values []float64) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min, max = math.Min(min, v), math.Max(max, v)
+	}
+	if math.IsInf(min, 0) {
+		return 0, 1
+	}
+	if min == max {
+		return min - 1, max + 1
+	}
+	pad := (max - min) * 0.05
+	return min - pad, max + pad
+}
+
+// Returns s escaped for use in SVG text and attributes.
+func escape(s string) string {
+	b := &bytes.Buffer{}
+	xml.EscapeText(b, []byte(s))
+	return b.String()
+}
+
+// WriteSVG writes plot to w as SVG image. Points with NaN or infinite coordinates are skipped.
+// Points are colored by group, groups are listed in legend sorted by name.
+func (s *Scatter) WriteSVG(w io.Writer) error {
+	width, height := s.Width, s.Height
+	if width == 0 {
+		width = DefaultWidth
+	}
+	if height == 0 {
+		height = DefaultHeight
+	}
+
+	points, xs, ys := []Point{}, []float64{}, []float64{}
+	groups, groupIndex := []string{}, map[string]int{}
+	for _, p := range s.Points {
+		if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+			continue
+		}
+		points, xs, ys = append(points, p), append(xs, p.X), append(ys, p.Y)
+		if _, ok := groupIndex[p.Group]; !ok {
+			groupIndex[p.Group] = 0
+			groups = append(groups, p.Group)
+		}
+	}
+	sort.Strings(groups)
+	for i, g := range groups {
+		groupIndex[g] = i
+	}
+	xMin, xMax := paddedRange(xs)
+	yMin, yMax := paddedRange(ys)
+
+	plotW, plotH := float64(width-marginLeft-marginRight), float64(height-marginTop-marginBottom)
+	px := func(x float64) float64 { return marginLeft + (x-xMin)/(xMax-xMin)*plotW }
+	py := func(y float64) float64 { return marginTop + (yMax-y)/(yMax-yMin)*plotH }
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	fmt.Fprintf(b, `<text x="%g" y="%d" text-anchor="middle" font-size="14" font-weight="bold">%s</text>`+"\n", marginLeft+plotW/2, marginTop/2+5, escape(s.Title))
+
+	// Grid, ticks and tick labels.
+	for _, t := range niceTicks(xMin, xMax, 6) {
+		x := px(t)
+		fmt.Fprintf(b, `<line x1="%.2f" y1="%d" x2="%.2f" y2="%.2f" stroke="#e0e0e0"/>`+"\n", x, marginTop, x, marginTop+plotH)
+		fmt.Fprintf(b, `<text x="%.2f" y="%.2f" text-anchor="middle">%g</text>`+"\n", x, marginTop+plotH+16, t)
+	}
+	for _, t := range niceTicks(yMin, yMax, 6) {
+		y := py(t)
+		fmt.Fprintf(b, `<line x1="%d" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#e0e0e0"/>`+"\n", marginLeft, y, marginLeft+plotW, y)
+		fmt.Fprintf(b, `<text x="%d" y="%.2f" text-anchor="end">%g</text>`+"\n", marginLeft-6, y+4, t)
+	}
+	fmt.Fprintf(b, `<rect x="%d" y="%d" width="%g" height="%g" fill="none" stroke="black"/>`+"\n", marginLeft, marginTop, plotW, plotH)
+
+	// Axis labels.
+	fmt.Fprintf(b, `<text x="%g" y="%d" text-anchor="middle">%s</text>`+"\n", marginLeft+plotW/2, height-12, escape(s.XLabel))
+	fmt.Fprintf(b, `<text x="16" y="%g" text-anchor="middle" transform="rotate(-90 16 %g)">%s</text>`+"\n", marginTop+plotH/2, marginTop+plotH/2, escape(s.YLabel))
+
+	// Points.
+	for _, p := range points {
+		fmt.Fprintf(b, `<circle cx="%.2f" cy="%.2f" r="3" fill="%s" fill-opacity="0.7"/>`+"\n", px(p.X), py(p.Y), groupColor(groupIndex[p.Group], len(groups)))
+	}
+
+	// Curve, clipped to plot area.
+	if s.Curve != nil && len(points) > 0 {
+		id := fmt.Sprintf("plot-area-%d", atomic.AddInt64(&plotCounter, 1))
+		fmt.Fprintf(b, `<clipPath id="%s"><rect x="%d" y="%d" width="%g" height="%g"/></clipPath>`+"\n", id, marginLeft, marginTop, plotW, plotH)
+		fmt.Fprintf(b, `<polyline clip-path="url(#%s)" fill="none" stroke="black" stroke-width="2" points="`, id)
+		const steps = 200
+		for i := 0; i <= steps; i++ {
+			x := xMin + (xMax-xMin)*float64(i)/steps
+			y := s.Curve(x)
+			if math.IsNaN(y) || math.IsInf(y, 0) {
+				continue
+			}
+			fmt.Fprintf(b, "%.2f,%.2f ", px(x), py(y))
+		}
+		fmt.Fprint(b, `"/>`+"\n")
+	}
+
+	// Legend, lines are squeezed to fit plot height.
+	lx, ly := float64(width-marginRight+15), float64(marginTop+10)
+	step := math.Min(16, (plotH-10)/float64(len(groups)+1))
+	fontSize := math.Min(12, step-1)
+	for i, g := range groups {
+		fmt.Fprintf(b, `<circle cx="%g" cy="%.2f" r="%g" fill="%s"/>`+"\n", lx, ly, math.Min(4, step/3), groupColor(i, len(groups)))
+		fmt.Fprintf(b, `<text x="%g" y="%.2f" font-size="%.1f">%s</text>`+"\n", lx+10, ly+fontSize/3, fontSize, escape(g))
+		ly += step
+	}
+	if s.Curve != nil && s.CurveLabel != "" {
+		fmt.Fprintf(b, `<line x1="%g" y1="%.2f" x2="%g" y2="%.2f" stroke="black" stroke-width="2"/>`+"\n", lx-6, ly, lx+4, ly)
+		fmt.Fprintf(b, `<text x="%g" y="%.2f" font-size="%.1f">%s</text>`+"\n", lx+10, ly+fontSize/3, fontSize, escape(s.CurveLabel))
+	}
+	fmt.Fprintln(b, "</svg>")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteSVGFile writes plot to SVG file on path.
+func (s *Scatter) WriteSVGFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := s.WriteSVG(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
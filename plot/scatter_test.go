@@ -0,0 +1,141 @@
+package plot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Parsed SVG document: counts of elements by name, attribute values and texts.
+type svgDocument struct {
+	root     string
+	elements map[string]int
+	attrs    []xml.Attr
+	texts    []string
+}
+
+// Returns SVG plot s parsed by XML decoder, fails test if output is not well-formed.
+func parseSVG(t *testing.T, s *Scatter) svgDocument {
+	t.Helper()
+	b := &bytes.Buffer{}
+	if err := s.WriteSVG(b); err != nil {
+		t.Fatalf("WriteSVG error: %v", err)
+	}
+
+	doc := svgDocument{elements: map[string]int{}}
+	d := xml.NewDecoder(b)
+	d.Strict = true
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG is not well-formed: %v\n%s", err, b)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if doc.root != "" {
+					t.Fatalf("SVG has more root elements")
+				}
+				doc.root = tok.Name.Space + " " + tok.Name.Local
+			}
+			depth++
+			doc.elements[tok.Name.Local]++
+			doc.attrs = append(doc.attrs, tok.Attr...)
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if s := strings.TrimSpace(string(tok)); s != "" {
+				doc.texts = append(doc.texts, s)
+			}
+		}
+	}
+	if depth != 0 {
+		t.Fatalf("SVG has unclosed elements")
+	}
+	return doc
+}
+
+// Fails test if some attribute of doc contains NaN or infinite number.
+func checkNumbers(t *testing.T, name string, doc svgDocument) {
+	t.Helper()
+	for _, a := range doc.attrs {
+		if strings.Contains(a.Value, "NaN") || strings.Contains(a.Value, "Inf") {
+			t.Errorf("%s: attribute %s=%q", name, a.Name.Local, a.Value)
+		}
+	}
+}
+
+func TestWriteSVGWellFormed(t *testing.T) {
+	points := []Point{}
+	for i := 0; i < 50; i++ {
+		x := float64(i) / 10
+		points = append(points, Point{x, 1 / (1 + math.Exp(-x+2)), fmt.Sprintf("group <%d> & co", i%3)})
+	}
+	points = append(points, Point{math.NaN(), 1, "skipped"}, Point{1, math.Inf(1), "skipped"})
+	s := &Scatter{
+		Title:      `SSIM "scatter" <&>`,
+		XLabel:     "SSIM (higher-is-better)",
+		YLabel:     "MOS",
+		Points:     points,
+		Curve:      func(x float64) float64 { return 1 / (1 + math.Exp(-x+2)) },
+		CurveLabel: "logistic5 fit",
+	}
+	doc := parseSVG(t, s)
+	if doc.root != "http://www.w3.org/2000/svg svg" {
+		t.Errorf("root element %q, want svg in SVG namespace", doc.root)
+	}
+	// Points and legend markers of 3 groups, skipped points are not drawn.
+	if n := doc.elements["circle"]; n != 50+3 {
+		t.Errorf("%d circles, want %d", n, 50+3)
+	}
+	if doc.elements["polyline"] != 1 || doc.elements["clipPath"] != 1 {
+		t.Errorf("%d curves with %d clip paths, want 1 curve with clip path", doc.elements["polyline"], doc.elements["clipPath"])
+	}
+	for _, want := range []string{s.Title, "group <1> & co", "logistic5 fit"} {
+		found := false
+		for _, text := range doc.texts {
+			found = found || text == want
+		}
+		if !found {
+			t.Errorf("text %q not found in SVG", want)
+		}
+	}
+	checkNumbers(t, "plot", doc)
+
+	// Every plot has own clip path id, so more plots can be embedded into single HTML document.
+	ids := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		for _, a := range parseSVG(t, s).attrs {
+			if a.Name.Local == "id" {
+				if ids[a.Value] {
+					t.Errorf("id %q used by more plots", a.Value)
+				}
+				ids[a.Value] = true
+			}
+		}
+	}
+}
+
+func TestWriteSVGDegenerate(t *testing.T) {
+	many := []Point{}
+	for i := 0; i < 40; i++ {
+		many = append(many, Point{float64(i), float64(i % 7), fmt.Sprint("g", i)})
+	}
+	for name, s := range map[string]*Scatter{
+		"empty":        {Title: "empty", Curve: math.Sqrt},
+		"single point": {Points: []Point{{1, 2, ""}}, Curve: func(float64) float64 { return math.NaN() }},
+		"all NaN":      {Points: []Point{{math.NaN(), math.NaN(), "a"}}},
+		"many groups":  {Points: many, Width: 300, Height: 200},
+	} {
+		doc := parseSVG(t, s)
+		checkNumbers(t, name, doc)
+	}
+}
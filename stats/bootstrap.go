@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// BootstrapMethod is a method for computing bootstrap confidence intervals.
+type BootstrapMethod int
+
+const (
+	Percentile BootstrapMethod = iota // percentiles of bootstrap distribution
+	BCa                               // bias-corrected and accelerated percentiles
+)
+
+// BootstrapConfig holds bootstrap resampling parameters.
+type BootstrapConfig struct {
+	Resamples int             // number of bootstrap resamples
+	Level     float64         // confidence level of intervals, e.g. 0.95
+	Method    BootstrapMethod // confidence intervals method
+	Seed      int64           // seed of random numbers generator, same seed gives same results
+}
+
+// DefaultBootstrapConfig is configuration with 1000 resamples and 95% BCa confidence intervals.
+var DefaultBootstrapConfig = BootstrapConfig{
+	Resamples: 1000,
+	Level:     0.95,
+	Method:    BCa,
+	Seed:      1,
+}
+
+// BootstrapResult holds results of bootstrap resampling of an evaluator.
+type BootstrapResult struct {
+	Estimate     float64 // evaluator value on original inputs
+	Mean         float64 // mean of bootstrap distribution
+	StdErr       float64 // standart error (standart deviation of bootstrap distribution)
+	Lower, Upper float64 // confidence interval bounds
+	Resamples    int     // number of resamples with valid (not NaN) evaluator value
+}
+
+// Bootstrap returns bootstrap estimates of evaluator ev (e.g. SROCC) on inputs a, b. Pairs (a[i], b[i]) are resampled with replacement.
+// Resamples, for which evaluator returns NaN, are omitted.
+// Using: B. Efron and R. J. Tibshirani, "An Introduction to the Bootstrap," Chapman & Hall, 1993.
+func Bootstrap(ev func([]float64, []float64) float64, a, b []float64, config BootstrapConfig) (BootstrapResult, error) {
+	if len(a) != len(b) {
+		return BootstrapResult{}, ErrLengthMismatch
+	}
+	if len(a) < 2 {
+		return BootstrapResult{}, errors.New("not enough input values")
+	}
+	if config.Resamples < 1 {
+		return BootstrapResult{}, errors.New("number of resamples has to be positive")
+	}
+	if config.Level <= 0 || config.Level >= 1 {
+		return BootstrapResult{}, errors.New("confidence level has to be in range (0, 1)")
+	}
+
+	n := len(a)
+	res := BootstrapResult{Estimate: ev(a, b)}
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	ra, rb := make([]float64, n), make([]float64, n)
+	dist := make([]float64, 0, config.Resamples)
+	for r := 0; r < config.Resamples; r++ {
+		for i := range ra {
+			j := rng.Intn(n)
+			ra[i], rb[i] = a[j], b[j]
+		}
+		if v := ev(ra, rb); !math.IsNaN(v) {
+			dist = append(dist, v)
+		}
+	}
+	if len(dist) == 0 {
+		return BootstrapResult{}, errors.New("evaluator returned NaN for all resamples")
+	}
+	sort.Float64s(dist)
+	res.Resamples = len(dist)
+	res.Mean, res.StdErr = meanSd(dist)
+
+	alpha := (1 - config.Level) / 2
+	lowerQ, upperQ := alpha, 1-alpha
+	if config.Method == BCa {
+		if math.IsNaN(res.Estimate) {
+			return BootstrapResult{}, errors.New("evaluator returned NaN for original inputs")
+		}
+
+		// Bias correction.
+		less := 0
+		for _, v := range dist {
+			if v < res.Estimate {
+				less++
+			}
+		}
+		z0 := normalQuantile(float64(less) / float64(len(dist)))
+
+		// Acceleration estimated by jackknife.
+		jack := make([]float64, 0, n)
+		ja, jb := make([]float64, n-1), make([]float64, n-1)
+		for i := 0; i < n; i++ {
+			copy(ja, a[:i])
+			copy(ja[i:], a[i+1:])
+			copy(jb, b[:i])
+			copy(jb[i:], b[i+1:])
+			if v := ev(ja, jb); !math.IsNaN(v) {
+				jack = append(jack, v)
+			}
+		}
+		jackMean := MeanA(jack)
+		num, den := 0.0, 0.0
+		for _, v := range jack {
+			d := jackMean - v
+			num += d * d * d
+			den += d * d
+		}
+		acc := 0.0
+		if den > 0 {
+			acc = num / (6 * math.Pow(den, 1.5))
+		}
+
+		adjust := func(q float64) float64 {
+			z := normalQuantile(q)
+			return normalCDF(z0 + (z0+z)/(1-acc*(z0+z)))
+		}
+		if !math.IsInf(z0, 0) {
+			lowerQ, upperQ = adjust(lowerQ), adjust(upperQ)
+		}
+	}
+	res.Lower, res.Upper = Quantile(dist, lowerQ), Quantile(dist, upperQ)
+	return res, nil
+}
+
+// Returns standard normal cumulative distribution function value of x.
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// Returns standard normal quantile function value (inverse of cumulative distribution function) of probability p.
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
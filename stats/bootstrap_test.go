@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Returns n pairs of correlated values generated from seed.
+func correlatedData(n int, seed int64) (a, b []float64) {
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < n; i++ {
+		v := r.NormFloat64()
+		a, b = append(a, v), append(b, v+r.NormFloat64()*0.5)
+	}
+	return
+}
+
+func TestBootstrapReproducible(t *testing.T) {
+	a, b := correlatedData(100, 1)
+	for _, method := range []BootstrapMethod{Percentile, BCa} {
+		config := BootstrapConfig{Resamples: 500, Level: 0.95, Method: method, Seed: 7}
+		res1, err := Bootstrap(PLCC, a, b, config)
+		if err != nil {
+			t.Fatalf("method %d: Bootstrap error: %v", method, err)
+		}
+		res2, _ := Bootstrap(PLCC, a, b, config)
+		if res1 != res2 {
+			t.Errorf("method %d: results with same seed differ: %+v, %+v", method, res1, res2)
+		}
+		config.Seed = 8
+		res3, _ := Bootstrap(PLCC, a, b, config)
+		if res1 == res3 {
+			t.Errorf("method %d: results with different seeds are equal: %+v", method, res1)
+		}
+
+		if res1.Estimate != PLCC(a, b) {
+			t.Errorf("method %d: estimate %v, want PLCC %v", method, res1.Estimate, PLCC(a, b))
+		}
+		if !(res1.Lower < res1.Estimate && res1.Estimate < res1.Upper) {
+			t.Errorf("method %d: interval [%v, %v] does not contain estimate %v", method, res1.Lower, res1.Upper, res1.Estimate)
+		}
+		if math.Abs(res1.Mean-res1.Estimate) > 3*res1.StdErr || res1.StdErr <= 0 {
+			t.Errorf("method %d: mean %v, standart error %v, estimate %v", method, res1.Mean, res1.StdErr, res1.Estimate)
+		}
+		if res1.Resamples != config.Resamples {
+			t.Errorf("method %d: %d valid resamples, want %d", method, res1.Resamples, config.Resamples)
+		}
+	}
+}
+
+func TestBootstrapErrors(t *testing.T) {
+	a, b := correlatedData(10, 1)
+	if _, err := Bootstrap(PLCC, a, b[:5], DefaultBootstrapConfig); err != ErrLengthMismatch {
+		t.Errorf("different lengths: error %v, want %v", err, ErrLengthMismatch)
+	}
+	if _, err := Bootstrap(PLCC, a, b, BootstrapConfig{Resamples: 10, Level: 1}); err == nil {
+		t.Errorf("level 1: no error")
+	}
+}
@@ -1,7 +1,8 @@
-package main
+package stats
 
 import (
 	"math"
+	"sort"
 
 	"github.com/dgryski/go-onlinestats"
 	gostats "github.com/mcgrew/gostats"
@@ -29,8 +30,13 @@ func discordantPair(x1, y1, x2, y2 float64) bool {
 // KROCC returns Kendall’s rank order correlation coefficient of inputs a, b.
 // Using: https://web.archive.org/web/20181008171919/https://docs.scipy.org/doc/scipy/reference/generated/scipy.stats.kendalltau.html
 func KROCC(a, b []float64) float64 {
-	if len(a) != len(b) {
-		panic("Unexpected input lenghts")
+	return must(KROCCErr(a, b))
+}
+
+// KROCCErr is KROCC(...) returning ErrLengthMismatch instead of panic.
+func KROCCErr(a, b []float64) (float64, error) {
+	if err := checkLengths(a, b); err != nil {
+		return 0, err
 	}
 
 	ra, rb := Rank(a, Fractional), Rank(b, Fractional)
@@ -56,7 +62,99 @@ func KROCC(a, b []float64) float64 {
 			}
 		}
 	}
-	return float64(cn-dn) / math.Sqrt(float64((cn+dn+tan)*(cn+dn+tbn)))
+	return float64(cn-dn) / math.Sqrt(float64((cn+dn+tan)*(cn+dn+tbn))), nil
+}
+
+// KROCCknight returns Kendall’s rank order correlation coefficient (tau-b) of inputs a, b in O(n log n) time, using merge sort to count discordant pairs.
+// Using: W. R. Knight, "A Computer Method for Calculating Kendall's Tau with Ungrouped Data," Journal of the American Statistical Association, vol. 61, no. 314, pp. 436-439, 1966.
+func KROCCknight(a, b []float64) float64 {
+	return must(KROCCknightErr(a, b))
+}
+
+// KROCCknightErr is KROCCknight(...) returning ErrLengthMismatch instead of panic.
+func KROCCknightErr(a, b []float64) (float64, error) {
+	if err := checkLengths(a, b); err != nil {
+		return 0, err
+	}
+
+	n := len(a)
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool {
+		if a[idx[i]] != a[idx[j]] {
+			return a[idx[i]] < a[idx[j]]
+		}
+		return b[idx[i]] < b[idx[j]]
+	})
+
+	// Pairs tied in a (n1) and tied in both a and b (n3).
+	n1, n3 := int64(0), int64(0)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && a[idx[j]] == a[idx[i]] {
+			j++
+		}
+		n1 += int64(j-i) * int64(j-i-1) / 2
+		for k := i; k < j; {
+			l := k + 1
+			for l < j && b[idx[l]] == b[idx[k]] {
+				l++
+			}
+			n3 += int64(l-k) * int64(l-k-1) / 2
+			k = l
+		}
+		i = j
+	}
+
+	// Sorting by b counts swaps, which is number of discordant pairs.
+	ys := make([]float64, n)
+	for i, v := range idx {
+		ys[i] = b[v]
+	}
+	swaps := mergeSortCountSwaps(ys, make([]float64, n))
+
+	// Pairs tied in b (n2).
+	n2 := int64(0)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && ys[j] == ys[i] {
+			j++
+		}
+		n2 += int64(j-i) * int64(j-i-1) / 2
+		i = j
+	}
+
+	n0 := int64(n) * int64(n-1) / 2
+	return float64(n0-n1-n2+n3-2*swaps) / math.Sqrt(float64(n0-n1)*float64(n0-n2)), nil
+}
+
+// Sorts a in place using buf as temporary storage and returns number of swaps (inversions) bubble sort would need.
+func mergeSortCountSwaps(a, buf []float64) int64 {
+	if len(a) < 2 {
+		return 0
+	}
+
+	m := len(a) / 2
+	swaps := mergeSortCountSwaps(a[:m], buf[:m]) + mergeSortCountSwaps(a[m:], buf[m:])
+
+	i, j, k := 0, m, 0
+	for i < m && j < len(a) {
+		if a[j] < a[i] {
+			buf[k] = a[j]
+			swaps += int64(m - i)
+			j++
+		} else {
+			buf[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buf[k:], a[i:m])
+	copy(buf[k:], a[j:])
+	copy(a, buf[:len(a)])
+	return swaps
 }
 
 // KROCCgostats returns Kendall’s rank order correlation coefficient of inputs a, b using gostats implementation.
@@ -75,11 +173,16 @@ func KROCCgonum(a, b []float64) float64 {
 // SROCC returns Spearman’s rank order correlation coefficient of inputs a, b
 // Using: https://en.wikipedia.org/wiki/Spearman%27s_rank_correlation_coefficient#Definition_and_calculation
 func SROCC(a, b []float64) float64 {
-	if len(a) != len(b) {
-		panic("Unexpected input lenghts")
+	return must(SROCCErr(a, b))
+}
+
+// SROCCErr is SROCC(...) returning ErrLengthMismatch instead of panic.
+func SROCCErr(a, b []float64) (float64, error) {
+	if err := checkLengths(a, b); err != nil {
+		return 0, err
 	}
 
-	return PLCC(Rank(a, Fractional), Rank(b, Fractional))
+	return PLCCErr(Rank(a, Fractional), Rank(b, Fractional))
 }
 
 // SROCConlinestats returns Spearman’s rank order correlation coefficient of inputs a, b using go-onlinestats implementation
@@ -99,8 +202,13 @@ func SROCCgostats(a, b []float64) float64 {
 // PLCC returns Pearson’s linear correlation coefficient of inputs a, b
 // Using: https://en.wikipedia.org/wiki/Pearson_correlation_coefficient#For_a_sample
 func PLCC(a, b []float64) float64 {
-	if len(a) != len(b) {
-		panic("Unexpected input lenghts")
+	return must(PLCCErr(a, b))
+}
+
+// PLCCErr is PLCC(...) returning ErrLengthMismatch instead of panic.
+func PLCCErr(a, b []float64) (float64, error) {
+	if err := checkLengths(a, b); err != nil {
+		return 0, err
 	}
 
 	avgA, avgB := MeanA(a), MeanA(b)
@@ -111,7 +219,7 @@ func PLCC(a, b []float64) float64 {
 		stdASum += cA * cA
 		stdBSum += cB * cB
 	}
-	return cenMulSum / (math.Sqrt(stdASum) * math.Sqrt(stdBSum))
+	return cenMulSum / (math.Sqrt(stdASum) * math.Sqrt(stdBSum)), nil
 }
 
 // PLCCgonum returns Pearson’s linear correlation coefficient of inputs a, b using gonum implementation
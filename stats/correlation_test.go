@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Returns n values drawn from k distinct values (heavy ties for small k), generated by r.
+func tiedValues(r *rand.Rand, n, k int) []float64 {
+	res := make([]float64, n)
+	for i := range res {
+		res[i] = float64(r.Intn(k))
+	}
+	return res
+}
+
+// Returns Kendall's tau-b of a, b computed directly from definition by comparing all pairs.
+func bruteForceTauB(a, b []float64) float64 {
+	nc, nd, ta, tb := 0.0, 0.0, 0.0, 0.0
+	for i := range a {
+		for j := i + 1; j < len(a); j++ {
+			switch s := (a[i] - a[j]) * (b[i] - b[j]); {
+			case s > 0:
+				nc++
+			case s < 0:
+				nd++
+			case a[i] == a[j] && b[i] != b[j]:
+				ta++
+			case b[i] == b[j] && a[i] != a[j]:
+				tb++
+			}
+		}
+	}
+	return (nc - nd) / math.Sqrt((nc+nd+ta)*(nc+nd+tb))
+}
+
+func TestKROCCknight(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 300; iter++ {
+		n := 2 + r.Intn(60)
+		a, b := tiedValues(r, n, 1+r.Intn(6)), tiedValues(r, n, 1+r.Intn(6))
+		if iter%3 == 0 {
+			// Correlated inputs with ties.
+			for i := range b {
+				b[i] = math.Floor((a[i] + float64(r.Intn(3))) / 2)
+			}
+		}
+
+		knight, kendall := KROCCknight(a, b), KROCC(a, b)
+		if math.IsNaN(kendall) {
+			if !math.IsNaN(knight) {
+				t.Errorf("a = %v, b = %v: KROCCknight = %v, KROCC = NaN", a, b, knight)
+			}
+			continue
+		}
+		if math.Abs(knight-kendall) > 1e-12 {
+			t.Errorf("a = %v, b = %v: KROCCknight = %v, KROCC = %v", a, b, knight, kendall)
+		}
+
+		if brute := bruteForceTauB(a, b); math.Abs(knight-brute) > 1e-12 {
+			t.Errorf("a = %v, b = %v: KROCCknight = %v, tau-b from definition = %v", a, b, knight, brute)
+		}
+	}
+}
+
+// KROCCgonum computes tau-a and counts pairs with zero difference as concordant or discordant (by sign bit of zero),
+// so it can be compared with tau-b only on inputs without ties.
+func TestKROCCknightWithoutTies(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for iter := 0; iter < 100; iter++ {
+		n := 2 + r.Intn(100)
+		a, b := make([]float64, n), make([]float64, n)
+		for i, v := range r.Perm(n) {
+			a[i], b[i] = r.NormFloat64(), float64(v)+r.Float64()/2
+		}
+		if knight, gonum, kendall := KROCCknight(a, b), KROCCgonum(a, b), KROCC(a, b); math.Abs(knight-gonum) > 1e-12 || math.Abs(knight-kendall) > 1e-12 {
+			t.Errorf("n = %d: KROCCknight = %v, KROCCgonum = %v, KROCC = %v", n, knight, gonum, kendall)
+		}
+	}
+}
+
+func TestKROCCknightDegenerate(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		a, b []float64
+	}{
+		{"empty", []float64{}, []float64{}},
+		{"one value", []float64{1}, []float64{2}},
+		{"constant a", []float64{3, 3, 3, 3}, []float64{1, 2, 3, 4}},
+		{"constant b", []float64{1, 2, 3, 4}, []float64{5, 5, 5, 5}},
+	} {
+		if v := KROCCknight(c.a, c.b); !math.IsNaN(v) {
+			t.Errorf("%s: KROCCknight = %v, want NaN", c.name, v)
+		}
+		if v := KROCC(c.a, c.b); !math.IsNaN(v) {
+			t.Errorf("%s: KROCC = %v, want NaN", c.name, v)
+		}
+	}
+	if _, err := KROCCknightErr([]float64{1, 2}, []float64{1}); err != ErrLengthMismatch {
+		t.Errorf("different lengths: error %v, want %v", err, ErrLengthMismatch)
+	}
+}
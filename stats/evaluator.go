@@ -0,0 +1,38 @@
+package stats
+
+import "errors"
+
+// Errors returned by evaluators.
+var (
+	ErrLengthMismatch       = errors.New("unexpected input lengths")
+	ErrUnknownRankingMethod = errors.New("unknown ranking method")
+)
+
+// Evaluator evaluates agreement of objective scores b with subjective scores a.
+type Evaluator interface {
+	Evaluate(a, b []float64) (float64, error)
+}
+
+// EvaluatorFunc is an adapter to allow the use of ordinary functions as evaluators.
+type EvaluatorFunc func(a, b []float64) (float64, error)
+
+// Evaluate returns f(a, b).
+func (f EvaluatorFunc) Evaluate(a, b []float64) (float64, error) {
+	return f(a, b)
+}
+
+// Returns ErrLengthMismatch if inputs a, b have different lengths.
+func checkLengths(a, b []float64) error {
+	if len(a) != len(b) {
+		return ErrLengthMismatch
+	}
+	return nil
+}
+
+// Returns v if err is nil, panics otherwise. Used by convenience wrappers of error returning evaluators.
+func must(v float64, err error) float64 {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -0,0 +1,285 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// LogisticModel is nonlinear function mapping objective scores x to subjective scores using parameters beta.
+type LogisticModel struct {
+	Name string
+	// Number of parameters.
+	NumParams int
+	// Function returns mapped value of x.
+	Function func(beta []float64, x float64) float64
+	// Initial returns initial parameters estimate for fitting objective x to subjective y.
+	Initial func(x, y []float64) []float64
+}
+
+// Logistic4 is 4-parameter logistic function (VQEG, "Final report from the video quality experts group on the validation of objective models of video quality assessment, phase II", 2003):
+//
+//	f(x) = (β1 - β2) / (1 + exp(-(x - β3) / |β4|)) + β2
+var Logistic4 = LogisticModel{
+	Name:      "logistic4",
+	NumParams: 4,
+	Function: func(beta []float64, x float64) float64 {
+		return (beta[0]-beta[1])/(1+math.Exp(-(x-beta[2])/math.Abs(beta[3]))) + beta[1]
+	},
+	Initial: func(x, y []float64) []float64 {
+		beta := []float64{Max(y), Min(y), MeanA(x), Sd(x)}
+		if PLCC(x, y) < 0 {
+			beta[0], beta[1] = beta[1], beta[0]
+		}
+		return beta
+	},
+}
+
+// Logistic5 is 5-parameter logistic function with added linear term (H. R. Sheikh, M. F. Sabir and A. C. Bovik, "A statistical evaluation of recent full reference image quality assessment algorithms," IEEE Transactions on Image Processing, vol. 15, no. 11, pp. 3440-3451, Nov. 2006):
+//
+//	f(x) = β1 * (0.5 - 1 / (1 + exp(β2 * (x - β3)))) + β4 * x + β5
+var Logistic5 = LogisticModel{
+	Name:      "logistic5",
+	NumParams: 5,
+	Function: func(beta []float64, x float64) float64 {
+		return beta[0]*(0.5-1/(1+math.Exp(beta[1]*(x-beta[2])))) + beta[3]*x + beta[4]
+	},
+	Initial: func(x, y []float64) []float64 {
+		beta := []float64{Max(y) - Min(y), 1 / Sd(x), MeanA(x), 0, MeanA(y)}
+		if PLCC(x, y) < 0 {
+			beta[0] = -beta[0]
+		}
+		return beta
+	},
+}
+
+// LogisticFit holds result of fitting logistic model to data.
+type LogisticFit struct {
+	Model  LogisticModel
+	Params []float64
+	// Sum of squared residuals.
+	SSE float64
+}
+
+// Map returns fitted logistic function values of x.
+func (f LogisticFit) Map(x []float64) []float64 {
+	res := make([]float64, len(x))
+	for i, v := range x {
+		res[i] = f.Model.Function(f.Params, v)
+	}
+	return res
+}
+
+func (f LogisticFit) String() string {
+	return fmt.Sprintf("%s%v", f.Model.Name, f.Params)
+}
+
+// FitLogistic fits logistic model mapping objective scores x to subjective scores y using LevenbergMarquardt(...).
+func FitLogistic(x, y []float64, model LogisticModel) (LogisticFit, error) {
+	if len(x) != len(y) {
+		return LogisticFit{}, ErrLengthMismatch
+	}
+	for i := range x {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			return LogisticFit{}, errors.New("input contains NaN values")
+		}
+	}
+	if len(x) <= model.NumParams {
+		return LogisticFit{}, errors.New("not enough values")
+	}
+
+	params, sse, err := LevenbergMarquardt(model.Function, x, y, model.Initial(x, y), 1000)
+	if err != nil {
+		return LogisticFit{}, err
+	}
+	return LogisticFit{model, params, sse}, nil
+}
+
+// LevenbergMarquardt returns parameters of function f minimizing sum of squared residuals y[i] - f(beta, x[i]) and the sum, starting from beta0.
+// Jacobian is computed numerically using central differences. Iteration stops after maxIter iterations or if relative improvement is negligible.
+func LevenbergMarquardt(f func(beta []float64, x float64) float64, x, y, beta0 []float64, maxIter int) ([]float64, float64, error) {
+	n, p := len(x), len(beta0)
+	sse := func(beta []float64) float64 {
+		s := 0.0
+		for i := range x {
+			r := y[i] - f(beta, x[i])
+			s += r * r
+		}
+		return s
+	}
+
+	beta := append([]float64(nil), beta0...)
+	cur := sse(beta)
+	if math.IsNaN(cur) || math.IsInf(cur, 0) {
+		return nil, 0, errors.New("initial parameters produce invalid residuals")
+	}
+
+	lambda := 1e-3
+	jac := make([][]float64, n)
+	for i := range jac {
+		jac[i] = make([]float64, p)
+	}
+	for iter := 0; iter < maxIter; iter++ {
+		// Jacobian of f with respect to beta.
+		for j := 0; j < p; j++ {
+			h := 1e-6 * math.Max(math.Abs(beta[j]), 1)
+			bp, bm := append([]float64(nil), beta...), append([]float64(nil), beta...)
+			bp[j] += h
+			bm[j] -= h
+			for i := range x {
+				jac[i][j] = (f(bp, x[i]) - f(bm, x[i])) / (2 * h)
+			}
+		}
+
+		// Normal equations J'J and J'r.
+		jtj, jtr := make([][]float64, p), make([]float64, p)
+		for a := 0; a < p; a++ {
+			jtj[a] = make([]float64, p)
+			for i := range x {
+				r := y[i] - f(beta, x[i])
+				jtr[a] += jac[i][a] * r
+				for b := 0; b < p; b++ {
+					jtj[a][b] += jac[i][a] * jac[i][b]
+				}
+			}
+		}
+
+		improved := false
+		for !improved && lambda < 1e16 {
+			m := make([][]float64, p)
+			for a := range m {
+				m[a] = append([]float64(nil), jtj[a]...)
+				m[a][a] += lambda * math.Max(jtj[a][a], 1e-12)
+			}
+			delta, err := solveLinear(m, append([]float64(nil), jtr...))
+			if err != nil {
+				lambda *= 10
+				continue
+			}
+
+			next := make([]float64, p)
+			for j := range next {
+				next[j] = beta[j] + delta[j]
+			}
+			if s := sse(next); s < cur {
+				rel := (cur - s) / math.Max(cur, 1e-300)
+				beta, cur, improved = next, s, true
+				lambda /= 10
+				if rel < 1e-12 {
+					return beta, cur, nil
+				}
+			} else {
+				lambda *= 10
+			}
+		}
+		if !improved {
+			break // no further improvement possible
+		}
+	}
+	return beta, cur, nil
+}
+
+// Returns solution of linear equations system a * x = b using gaussian elimination with partial pivoting. Inputs are modified.
+func solveLinear(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	for c := 0; c < n; c++ {
+		pivot := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(a[r][c]) > math.Abs(a[pivot][c]) {
+				pivot = r
+			}
+		}
+		if a[pivot][c] == 0 || math.IsNaN(a[pivot][c]) {
+			return nil, errors.New("singular matrix")
+		}
+		a[c], a[pivot] = a[pivot], a[c]
+		b[c], b[pivot] = b[pivot], b[c]
+
+		for r := c + 1; r < n; r++ {
+			k := a[r][c] / a[c][c]
+			for j := c; j < n; j++ {
+				a[r][j] -= k * a[c][j]
+			}
+			b[r] -= k * b[c]
+		}
+	}
+
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		s := b[r]
+		for j := r + 1; j < n; j++ {
+			s -= a[r][j] * x[j]
+		}
+		x[r] = s / a[r][r]
+	}
+	return x, nil
+}
+
+// PLCCFitted returns Pearson’s linear correlation coefficient of subjective scores a and objective scores b mapped to a by fitted Logistic5 function.
+// Returns NaN if fitting fails, panics with ErrLengthMismatch if a, b have different lengths.
+func PLCCFitted(a, b []float64) float64 {
+	return nanOnError(PLCCFittedErr(a, b))
+}
+
+// PLCCFittedErr is PLCCFitted(...) returning fitting error instead of NaN.
+func PLCCFittedErr(a, b []float64) (float64, error) {
+	return plccFitted(a, b, Logistic5)
+}
+
+// RMSEFitted returns root mean square error between subjective scores a and objective scores b mapped to a by fitted Logistic5 function.
+// Returns NaN if fitting fails, panics with ErrLengthMismatch if a, b have different lengths.
+func RMSEFitted(a, b []float64) float64 {
+	return nanOnError(RMSEFittedErr(a, b))
+}
+
+// RMSEFittedErr is RMSEFitted(...) returning fitting error instead of NaN.
+func RMSEFittedErr(a, b []float64) (float64, error) {
+	return rmseFitted(a, b, Logistic5)
+}
+
+// PLCCFitted4 is same as PLCCFitted(...), but uses Logistic4 function.
+func PLCCFitted4(a, b []float64) float64 {
+	return nanOnError(PLCCFitted4Err(a, b))
+}
+
+// PLCCFitted4Err is PLCCFitted4(...) returning fitting error instead of NaN.
+func PLCCFitted4Err(a, b []float64) (float64, error) {
+	return plccFitted(a, b, Logistic4)
+}
+
+// RMSEFitted4 is same as RMSEFitted(...), but uses Logistic4 function.
+func RMSEFitted4(a, b []float64) float64 {
+	return nanOnError(RMSEFitted4Err(a, b))
+}
+
+// RMSEFitted4Err is RMSEFitted4(...) returning fitting error instead of NaN.
+func RMSEFitted4Err(a, b []float64) (float64, error) {
+	return rmseFitted(a, b, Logistic4)
+}
+
+func plccFitted(a, b []float64, model LogisticModel) (float64, error) {
+	fit, err := FitLogistic(b, a, model)
+	if err != nil {
+		return 0, err
+	}
+	return PLCCErr(a, fit.Map(b))
+}
+
+func rmseFitted(a, b []float64, model LogisticModel) (float64, error) {
+	fit, err := FitLogistic(b, a, model)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(fit.SSE / float64(len(a))), nil
+}
+
+// Returns v if err is nil, panics if err is ErrLengthMismatch (as other evaluators do) and returns NaN for other (fitting) errors.
+func nanOnError(v float64, err error) float64 {
+	if err == ErrLengthMismatch {
+		panic(err)
+	}
+	if err != nil {
+		return math.NaN()
+	}
+	return v
+}
@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLevenbergMarquardtExponential(t *testing.T) {
+	f := func(beta []float64, x float64) float64 { return beta[0] * math.Exp(beta[1]*x) }
+	want := []float64{2, 0.3}
+	x, y := []float64{}, []float64{}
+	for i := 0; i < 20; i++ {
+		x = append(x, float64(i)/2)
+		y = append(y, f(want, float64(i)/2))
+	}
+	beta, sse, err := LevenbergMarquardt(f, x, y, []float64{1, 0.1}, 1000)
+	if err != nil {
+		t.Fatalf("LevenbergMarquardt error: %v", err)
+	}
+	for i := range want {
+		if math.Abs(beta[i]-want[i]) > 1e-6 {
+			t.Errorf("beta = %v, want %v", beta, want)
+			break
+		}
+	}
+	if sse > 1e-10 {
+		t.Errorf("sse = %v, want 0", sse)
+	}
+}
+
+func TestFitLogistic4RecoversParameters(t *testing.T) {
+	want := []float64{90, 10, 0.5, 0.1}
+	r := rand.New(rand.NewSource(1))
+	x, y := []float64{}, []float64{}
+	for i := 0; i < 200; i++ {
+		v := r.Float64()
+		x = append(x, v)
+		y = append(y, Logistic4.Function(want, v)+r.NormFloat64()*0.01)
+	}
+	fit, err := FitLogistic(x, y, Logistic4)
+	if err != nil {
+		t.Fatalf("FitLogistic error: %v", err)
+	}
+	got := append([]float64(nil), fit.Params...)
+	got[3] = math.Abs(got[3]) // function uses |β4|
+	for i, tol := range []float64{0.1, 0.1, 0.005, 0.005} {
+		if math.Abs(got[i]-want[i]) > tol {
+			t.Errorf("params = %v, want %v", fit.Params, want)
+			break
+		}
+	}
+}
+
+func TestFitLogistic5(t *testing.T) {
+	want := []float64{80, 8, 0.5, 5, 20}
+	x, y := []float64{}, []float64{}
+	for i := 0; i <= 100; i++ {
+		v := float64(i) / 100
+		x = append(x, v)
+		y = append(y, Logistic5.Function(want, v))
+	}
+	fit, err := FitLogistic(x, y, Logistic5)
+	if err != nil {
+		t.Fatalf("FitLogistic error: %v", err)
+	}
+	for i, v := range fit.Map(x) {
+		if math.Abs(v-y[i]) > 1e-3 {
+			t.Fatalf("fitted value at %v = %v, want %v (params %v)", x[i], v, y[i], fit.Params)
+		}
+	}
+	if v := PLCCFitted(y, x); math.Abs(v-1) > 1e-9 {
+		t.Errorf("PLCCFitted = %v, want 1", v)
+	}
+}
+
+func TestFitLogisticErrors(t *testing.T) {
+	if _, err := FitLogistic([]float64{1, 2}, []float64{1}, Logistic5); err != ErrLengthMismatch {
+		t.Errorf("different lengths: error %v, want %v", err, ErrLengthMismatch)
+	}
+	if _, err := FitLogistic([]float64{1, 2, 3}, []float64{1, 2, 3}, Logistic5); err == nil {
+		t.Errorf("not enough values: no error")
+	}
+	if _, err := FitLogistic([]float64{1, 2, 3, 4, 5, math.NaN()}, []float64{1, 2, 3, 4, 5, 6}, Logistic4); err == nil {
+		t.Errorf("NaN values: no error")
+	}
+}
+
+func TestFittedEvaluatorsErrors(t *testing.T) {
+	// Fitting failure is NaN, different lengths panic as in other evaluators.
+	if v := PLCCFitted([]float64{1, 2, 3}, []float64{1, 2, 3}); !math.IsNaN(v) {
+		t.Errorf("PLCCFitted with not enough values = %v, want NaN", v)
+	}
+	defer func() {
+		if r := recover(); r != ErrLengthMismatch {
+			t.Errorf("RMSEFitted with different lengths recovered %v, want %v", r, ErrLengthMismatch)
+		}
+	}()
+	RMSEFitted([]float64{1, 2, 3, 4, 5, 6}, []float64{1, 2, 3})
+}
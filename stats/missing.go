@@ -0,0 +1,84 @@
+package stats
+
+import "math"
+
+// CompleteCases returns copies of columns without rows (indexes), where value of some column is NaN, and number of dropped rows.
+// All columns have to have equal lengths, panics with ErrLengthMismatch otherwise.
+func CompleteCases(columns ...[]float64) ([][]float64, int) {
+	res, dropped, err := CompleteCasesErr(columns...)
+	if err != nil {
+		panic(err)
+	}
+	return res, dropped
+}
+
+// CompleteCasesErr is CompleteCases(...) returning ErrLengthMismatch instead of panic.
+func CompleteCasesErr(columns ...[]float64) ([][]float64, int, error) {
+	if len(columns) == 0 {
+		return nil, 0, nil
+	}
+	n := len(columns[0])
+	for _, c := range columns {
+		if len(c) != n {
+			return nil, 0, ErrLengthMismatch
+		}
+	}
+
+	res := make([][]float64, len(columns))
+	for i := range res {
+		res[i] = make([]float64, 0, n)
+	}
+	dropped := 0
+	for i := 0; i < n; i++ {
+		complete := true
+		for _, c := range columns {
+			if math.IsNaN(c[i]) {
+				complete = false
+				break
+			}
+		}
+		if !complete {
+			dropped++
+			continue
+		}
+		for j, c := range columns {
+			res[j] = append(res[j], c[i])
+		}
+	}
+	return res, dropped, nil
+}
+
+// PairwiseComplete returns copies of a, b without pairs (a[i], b[i]) containing NaN value and number of dropped pairs.
+func PairwiseComplete(a, b []float64) ([]float64, []float64, int) {
+	res, dropped := CompleteCases(a, b)
+	return res[0], res[1], dropped
+}
+
+// PairwiseCompleteErr is PairwiseComplete(...) returning ErrLengthMismatch instead of panic.
+func PairwiseCompleteErr(a, b []float64) ([]float64, []float64, int, error) {
+	res, dropped, err := CompleteCasesErr(a, b)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return res[0], res[1], dropped, nil
+}
+
+// PairwiseCompleteEvaluator returns evaluator, which applies ev only to pairs (a[i], b[i]) without NaN values and returns its value along with number of dropped pairs.
+func PairwiseCompleteEvaluator(ev func([]float64, []float64) float64) func([]float64, []float64) (float64, int) {
+	return func(a, b []float64) (float64, int) {
+		ca, cb, dropped := PairwiseComplete(a, b)
+		return ev(ca, cb), dropped
+	}
+}
+
+// PairwiseCompleteEvaluatorErr is PairwiseCompleteEvaluator(...) for error returning evaluator ev. Returns ErrLengthMismatch if a, b have different lengths.
+func PairwiseCompleteEvaluatorErr(ev Evaluator) func([]float64, []float64) (float64, int, error) {
+	return func(a, b []float64) (float64, int, error) {
+		ca, cb, dropped, err := PairwiseCompleteErr(a, b)
+		if err != nil {
+			return 0, 0, err
+		}
+		v, err := ev.Evaluate(ca, cb)
+		return v, dropped, err
+	}
+}
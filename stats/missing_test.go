@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCompleteCases(t *testing.T) {
+	nan := math.NaN()
+	res, dropped := CompleteCases(
+		[]float64{1, nan, 3, 4, 5},
+		[]float64{1, 2, 3, nan, 5},
+		[]float64{1, 2, 3, 4, nan},
+	)
+	if dropped != 3 {
+		t.Errorf("dropped %d, want 3", dropped)
+	}
+	if want := [][]float64{{1, 3}, {1, 3}, {1, 3}}; !reflect.DeepEqual(res, want) {
+		t.Errorf("complete cases %v, want %v", res, want)
+	}
+
+	if res, dropped := CompleteCases(); res != nil || dropped != 0 {
+		t.Errorf("CompleteCases() = %v, %d, want nil, 0", res, dropped)
+	}
+}
+
+func TestPairwiseCompleteEvaluator(t *testing.T) {
+	nan := math.NaN()
+	a := []float64{1, 2, nan, 4, 5, 6}
+	b := []float64{2, 4, 6, 8, nan, 12}
+
+	if v := PLCC(a, b); !math.IsNaN(v) {
+		t.Errorf("PLCC with NaN values = %v, want NaN", v)
+	}
+
+	ev := PairwiseCompleteEvaluator(PLCC)
+	v, dropped := ev(a, b)
+	if dropped != 2 {
+		t.Errorf("dropped %d, want 2", dropped)
+	}
+	if math.Abs(v-1) > 1e-12 {
+		t.Errorf("pairwise complete PLCC = %v, want 1", v)
+	}
+
+	// Inputs without NaN values are evaluated as they are.
+	a, b = []float64{3, 1, 2}, []float64{1, 2, 3}
+	if v, dropped := ev(a, b); v != PLCC(a, b) || dropped != 0 {
+		t.Errorf("pairwise complete PLCC without NaN = %v, %d, want %v, 0", v, dropped, PLCC(a, b))
+	}
+}
+
+func TestPairwiseCompleteEvaluatorErr(t *testing.T) {
+	nan := math.NaN()
+	ev := PairwiseCompleteEvaluatorErr(EvaluatorFunc(SROCCErr))
+	v, dropped, err := ev([]float64{nan, 1, 2, 3}, []float64{5, 1, 2, nan})
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if math.Abs(v-1) > 1e-12 || dropped != 2 {
+		t.Errorf("pairwise complete SROCC = %v, %d, want 1, 2", v, dropped)
+	}
+
+	if _, _, err := ev([]float64{1, 2}, []float64{1}); err != ErrLengthMismatch {
+		t.Errorf("different lengths: error %v, want %v", err, ErrLengthMismatch)
+	}
+	if _, _, err := CompleteCasesErr([]float64{1, 2}, []float64{1, 2}, []float64{1}); err != ErrLengthMismatch {
+		t.Errorf("CompleteCasesErr with different lengths: error %v, want %v", err, ErrLengthMismatch)
+	}
+}
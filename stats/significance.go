@@ -0,0 +1,217 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// TestResult holds result of statistical hypothesis test.
+type TestResult struct {
+	Statistic float64 // test statistic value
+	PValue    float64 // two-sided p-value
+}
+
+// FTest returns variance ratio test of residuals ra and rb, e.g. of two metrics after logistic fitting to the same subjective scores.
+// Statistic is var(ra) / var(rb) with F(len(ra)-1, len(rb)-1) distribution, if residuals have equal variances.
+// Using: H. R. Sheikh, M. F. Sabir and A. C. Bovik, "A statistical evaluation of recent full reference image quality assessment algorithms," IEEE Transactions on Image Processing, vol. 15, no. 11, pp. 3440-3451, Nov. 2006.
+func FTest(ra, rb []float64) (TestResult, error) {
+	if len(ra) < 2 || len(rb) < 2 {
+		return TestResult{}, errors.New("not enough residuals")
+	}
+
+	_, sdA := meanSd(ra)
+	_, sdB := meanSd(rb)
+	f := (sdA * sdA) / (sdB * sdB)
+	cdf := fCDF(f, float64(len(ra)-1), float64(len(rb)-1))
+	return TestResult{f, 2 * math.Min(cdf, 1-cdf)}, nil
+}
+
+// WilliamsTest returns Williams' t-test of difference between dependent correlations r12 and r13, which share variable 1, where r23 is correlation of variables 2 and 3, for n samples.
+// Statistic has t distribution with n-3 degrees of freedom.
+// Using: J. H. Steiger, "Tests for comparing elements of a correlation matrix," Psychological Bulletin, vol. 87, no. 2, pp. 245-251, 1980.
+func WilliamsTest(r12, r13, r23 float64, n int) (TestResult, error) {
+	if n < 4 {
+		return TestResult{}, errors.New("not enough samples")
+	}
+
+	fn := float64(n)
+	det := 1 - r12*r12 - r13*r13 - r23*r23 + 2*r12*r13*r23
+	rm := (r12 + r13) / 2
+	t := (r12 - r13) * math.Sqrt((fn-1)*(1+r23)/(2*(fn-1)/(fn-3)*det+rm*rm*math.Pow(1-r23, 3)))
+	return TestResult{t, tTwoSidedP(t, fn-3)}, nil
+}
+
+// SteigerZTest returns Steiger's z-test of difference between dependent correlations r12 and r13, which share variable 1, where r23 is correlation of variables 2 and 3, for n samples.
+// Statistic has standard normal distribution.
+// Using: J. H. Steiger, "Tests for comparing elements of a correlation matrix," Psychological Bulletin, vol. 87, no. 2, pp. 245-251, 1980.
+func SteigerZTest(r12, r13, r23 float64, n int) (TestResult, error) {
+	if n < 4 {
+		return TestResult{}, errors.New("not enough samples")
+	}
+
+	rm := (r12 + r13) / 2
+	rm2 := rm * rm
+	s := (r23*(1-2*rm2) - 0.5*rm2*(1-2*rm2-r23*r23)) / ((1 - rm2) * (1 - rm2))
+	z := (math.Atanh(r12) - math.Atanh(r13)) * math.Sqrt(float64(n-3)) / math.Sqrt(2-2*s)
+	return TestResult{z, 2 * (1 - normalCDF(math.Abs(z)))}, nil
+}
+
+// FisherZTest returns test of difference between independent correlations r1 (from n1 samples) and r2 (from n2 samples) using Fisher z-transform.
+// Statistic has standard normal distribution.
+func FisherZTest(r1 float64, n1 int, r2 float64, n2 int) (TestResult, error) {
+	if n1 < 4 || n2 < 4 {
+		return TestResult{}, errors.New("not enough samples")
+	}
+
+	z := (math.Atanh(r1) - math.Atanh(r2)) / math.Sqrt(1/float64(n1-3)+1/float64(n2-3))
+	return TestResult{z, 2 * (1 - normalCDF(math.Abs(z)))}, nil
+}
+
+// SignificanceTest is a test used for comparing metrics.
+type SignificanceTest int
+
+const (
+	FTestResiduals SignificanceTest = iota // F-test of residuals after logistic fitting
+	Williams                               // Williams' test of fitted PLCCs
+	Steiger                                // Steiger's z-test of fitted PLCCs
+	FisherZ                                // Fisher z-transform test of fitted PLCCs (as independent)
+)
+
+// SignificanceMatrix compares every pair of objective metrics scores (rows of objective) fitted by model to subjective scores mos using test.
+// Returned signs[i][j] is 1 if metric i is significantly better than metric j at significance level alpha, -1 if significantly worse and 0 if difference is not significant (or could not be tested).
+// Returned pvalues[i][j] are p-values of tests (NaN if not tested).
+func SignificanceMatrix(mos []float64, objective [][]float64, model LogisticModel, test SignificanceTest, alpha float64) (signs [][]int, pvalues [][]float64) {
+	n := len(objective)
+	fits, fitted, residuals, plccs := make([]error, n), make([][]float64, n), make([][]float64, n), make([]float64, n)
+	for i, obj := range objective {
+		fit, err := FitLogistic(obj, mos, model)
+		fits[i] = err
+		if err != nil {
+			continue
+		}
+		fitted[i] = fit.Map(obj)
+		residuals[i] = make([]float64, len(mos))
+		for k := range mos {
+			residuals[i][k] = mos[k] - fitted[i][k]
+		}
+		plccs[i] = PLCC(mos, fitted[i])
+	}
+
+	signs, pvalues = make([][]int, n), make([][]float64, n)
+	for i := range signs {
+		signs[i], pvalues[i] = make([]int, n), make([]float64, n)
+		for j := range signs[i] {
+			pvalues[i][j] = math.NaN()
+			if i == j || fits[i] != nil || fits[j] != nil {
+				continue
+			}
+
+			var res TestResult
+			var err error
+			better := false
+			switch test {
+			case FTestResiduals:
+				res, err = FTest(residuals[i], residuals[j])
+				better = res.Statistic < 1
+			case Williams:
+				res, err = WilliamsTest(plccs[i], plccs[j], PLCC(fitted[i], fitted[j]), len(mos))
+				better = res.Statistic > 0
+			case Steiger:
+				res, err = SteigerZTest(plccs[i], plccs[j], PLCC(fitted[i], fitted[j]), len(mos))
+				better = res.Statistic > 0
+			case FisherZ:
+				res, err = FisherZTest(plccs[i], len(mos), plccs[j], len(mos))
+				better = res.Statistic > 0
+			default:
+				err = errors.New("unknown significance test")
+			}
+			if err != nil || math.IsNaN(res.PValue) {
+				continue
+			}
+
+			pvalues[i][j] = res.PValue
+			if res.PValue < alpha {
+				signs[i][j] = -1
+				if better {
+					signs[i][j] = 1
+				}
+			}
+		}
+	}
+	return
+}
+
+// Returns cumulative distribution function value of F distribution with d1, d2 degrees of freedom at x.
+func fCDF(x, d1, d2 float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return regIncBeta(d1/2, d2/2, d1*x/(d1*x+d2))
+}
+
+// Returns two-sided p-value of t statistic with df degrees of freedom.
+func tTwoSidedP(t, df float64) float64 {
+	return regIncBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// Returns regularized incomplete beta function I_x(a, b).
+// Using: W. H. Press et al., "Numerical Recipes in C," 2nd ed., Cambridge University Press, 1992, section 6.4.
+func regIncBeta(a, b, x float64) float64 {
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// Returns continued fraction for incomplete beta function, evaluated by modified Lentz's method.
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIter = 300
+		eps     = 3e-16
+		tiny    = 1e-300
+	)
+
+	qab, qap, qam := a+b, a+1, a-1
+	c, d := 1.0, 1-qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+		for _, aa := range []float64{
+			fm * (b - fm) * x / ((qam + m2) * (a + m2)),
+			-(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2)),
+		} {
+			d = 1 + aa*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + aa/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < eps {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// Reference values of Williams' t and Steiger's z statistics and their two-sided p-values, computed independently from formulas in
+// J. H. Steiger, "Tests for comparing elements of a correlation matrix," Psychological Bulletin, vol. 87, no. 2, pp. 245-251, 1980
+// (p-values by numerical integration of t distribution density and by complementary error function).
+var dependentCorrelationCases = []struct {
+	r12, r13, r23 float64
+	n             int
+	williams      TestResult
+	steiger       TestResult
+}{
+	{0.5, 0.4, 0.6, 103, TestResult{1.2959270886192018, 0.19798374055979365}, TestResult{1.2877311031336678, 0.19783958259084622}},
+	{0.7, 0.55, 0.8, 30, TestResult{1.7193395814182844, 0.09699864133168057}, TestResult{1.6502072674308685, 0.0989005509393198}},
+}
+
+func checkTestResult(t *testing.T, name string, got, want TestResult) {
+	t.Helper()
+	if math.Abs(got.Statistic-want.Statistic) > 1e-9 || math.Abs(got.PValue-want.PValue) > 1e-6 {
+		t.Errorf("%s = %+v, want %+v", name, got, want)
+	}
+}
+
+func TestWilliamsTest(t *testing.T) {
+	for _, c := range dependentCorrelationCases {
+		res, err := WilliamsTest(c.r12, c.r13, c.r23, c.n)
+		if err != nil {
+			t.Fatalf("WilliamsTest error: %v", err)
+		}
+		checkTestResult(t, "WilliamsTest", res, c.williams)
+
+		// Swapped correlations give opposite statistic with the same p-value.
+		res, _ = WilliamsTest(c.r13, c.r12, c.r23, c.n)
+		checkTestResult(t, "swapped WilliamsTest", res, TestResult{-c.williams.Statistic, c.williams.PValue})
+	}
+	if res, _ := WilliamsTest(0.5, 0.5, 0.3, 50); res.Statistic != 0 || math.Abs(res.PValue-1) > 1e-12 {
+		t.Errorf("WilliamsTest of equal correlations = %+v, want statistic 0 and p-value 1", res)
+	}
+	if _, err := WilliamsTest(0.5, 0.4, 0.3, 3); err == nil {
+		t.Errorf("WilliamsTest with 3 samples: no error")
+	}
+}
+
+func TestSteigerZTest(t *testing.T) {
+	for _, c := range dependentCorrelationCases {
+		res, err := SteigerZTest(c.r12, c.r13, c.r23, c.n)
+		if err != nil {
+			t.Fatalf("SteigerZTest error: %v", err)
+		}
+		checkTestResult(t, "SteigerZTest", res, c.steiger)
+	}
+	if _, err := SteigerZTest(0.5, 0.4, 0.3, 3); err == nil {
+		t.Errorf("SteigerZTest with 3 samples: no error")
+	}
+}
+
+func TestFisherZTest(t *testing.T) {
+	// z = (atanh(0.6) - atanh(0.4)) / sqrt(1/97 + 1/97).
+	res, err := FisherZTest(0.6, 100, 0.4, 100)
+	if err != nil {
+		t.Fatalf("FisherZTest error: %v", err)
+	}
+	z := (math.Atanh(0.6) - math.Atanh(0.4)) / math.Sqrt(2.0/97)
+	checkTestResult(t, "FisherZTest", res, TestResult{z, math.Erfc(z / math.Sqrt2)})
+}
@@ -1,4 +1,5 @@
-package main
+// Package stats implements statistical functions, ranking and correlation evaluators used to compare image quality metrics with subjective scores.
+package stats
 
 import (
 	"math"
@@ -7,19 +8,24 @@ import (
 
 // RMSE returns root mean square error of inputs a, b which are centered around avgerage and normalized/divaded by standart deviation.
 func RMSE(a, b []float64) float64 {
-	if len(a) != len(b) {
-		panic("Unexpected input lenghts")
+	return must(RMSEErr(a, b))
+}
+
+// RMSEErr is RMSE(...) returning ErrLengthMismatch instead of panic.
+func RMSEErr(a, b []float64) (float64, error) {
+	if err := checkLengths(a, b); err != nil {
+		return 0, err
 	}
 
 	avgA, sdA := meanSd(a)
-	avgB, sdB := meanSd(a)
+	avgB, sdB := meanSd(b)
 
 	errSqrSum := 0.0
 	for i := range a {
 		cnA, cnB := (a[i]-avgA)/sdA, (b[i]-avgB)/sdB
 		errSqrSum += (cnB - cnA) * (cnB - cnA)
 	}
-	return math.Sqrt(errSqrSum / float64(len(a)))
+	return math.Sqrt(errSqrSum / float64(len(a))), nil
 }
 
 // NRMSE_Sd returns root mean square error normalized/divided by standart deviation of a.
@@ -139,6 +145,15 @@ const (
 
 // Rank returns ranks for input a
 func Rank(a []float64, m RankingMetod) []float64 {
+	res, err := RankErr(a, m)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// RankErr is Rank(...) returning ErrUnknownRankingMethod instead of panic.
+func RankErr(a []float64, m RankingMetod) ([]float64, error) {
 	rs := make([]struct {
 		v float64
 		i int
@@ -200,7 +215,7 @@ func Rank(a []float64, m RankingMetod) []float64 {
 			res[r.i] = float64(i + 1)
 		}
 	default:
-		panic("unknown ranking method")
+		return nil, ErrUnknownRankingMethod
 	}
-	return res
+	return res, nil
 }
@@ -0,0 +1,23 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRMSE(t *testing.T) {
+	a := []float64{1, 2, 3, 4, 5}
+
+	// Both inputs are standardized, so linear transformation of a has zero error.
+	if v := RMSE(a, []float64{3, 5, 7, 9, 11}); math.Abs(v) > 1e-12 {
+		t.Errorf("RMSE(a, 2a+1) = %v, want 0", v)
+	}
+	// Standardized reversed input differs by 2*z(a), z uses sample standart deviation, so sqrt(mean(4*z^2)) = 2*sqrt((n-1)/n).
+	if v, want := RMSE(a, []float64{50, 40, 30, 20, 10}), 2*math.Sqrt(0.8); math.Abs(v-want) > 1e-12 {
+		t.Errorf("RMSE(a, reversed) = %v, want %v", v, want)
+	}
+
+	if _, err := RMSEErr(a, a[:3]); err != ErrLengthMismatch {
+		t.Errorf("different lengths: error %v, want %v", err, ErrLengthMismatch)
+	}
+}
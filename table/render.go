@@ -0,0 +1,270 @@
+package table
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RenderText renders table as text with right aligned columns, followed by empty line. Best values are marked by asterisk.
+func RenderText(w io.Writer, t *Table, opts Options) error {
+	best := t.bestCells(opts.BoldBest)
+	cells := make([][]string, len(t.Rows))
+	for i, r := range t.Rows {
+		cells[i] = make([]string, len(t.Columns))
+		for j, c := range r.Cells[:len(t.Columns)] {
+			cells[i][j] = t.format(c, j)
+			if best[i][j] {
+				cells[i][j] += "*"
+			}
+		}
+	}
+
+	nameWidth := 10
+	for _, r := range t.Rows {
+		if utf8.RuneCountInString(r.Name)+1 > nameWidth {
+			nameWidth = utf8.RuneCountInString(r.Name) + 1
+		}
+	}
+	widths := make([]int, len(t.Columns))
+	for j, c := range t.Columns {
+		widths[j] = 10
+		if utf8.RuneCountInString(c.Name)+1 > widths[j] {
+			widths[j] = utf8.RuneCountInString(c.Name) + 1
+		}
+		for i := range cells {
+			if utf8.RuneCountInString(cells[i][j])+2 > widths[j] {
+				widths[j] = utf8.RuneCountInString(cells[i][j]) + 2
+			}
+		}
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s\n\n", t.Title)
+	fmt.Fprintf(b, "%*s", nameWidth, t.Corner)
+	for j, c := range t.Columns {
+		fmt.Fprintf(b, "%*s", widths[j], c.Name)
+	}
+	fmt.Fprintln(b)
+	for i, r := range t.Rows {
+		fmt.Fprintf(b, "%*s", nameWidth, r.Name)
+		for j := range t.Columns {
+			fmt.Fprintf(b, "%*s", widths[j], cells[i][j])
+		}
+		fmt.Fprintln(b)
+	}
+	fmt.Fprintln(b)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RenderCSV renders table as CSV with header (corner and column names). Numeric values are written with full precision, best values are not marked.
+// Title is written only if CSVTitles option is set, as comment lines starting with "# " before header, and empty line follows table then.
+func RenderCSV(w io.Writer, t *Table, opts Options) error {
+	if opts.CSVTitles {
+		for _, line := range strings.Split(t.Title, "\n") {
+			if _, err := fmt.Fprintf(w, "# %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	cw := csv.NewWriter(w)
+	header := []string{t.Corner}
+	for _, c := range t.Columns {
+		header = append(header, c.Name)
+	}
+	cw.Write(header)
+	for _, r := range t.Rows {
+		record := []string{r.Name}
+		for _, c := range r.Cells[:len(t.Columns)] {
+			if c.IsText() {
+				record = append(record, c.Text)
+			} else {
+				record = append(record, strconv.FormatFloat(c.Value, 'g', -1, 64))
+			}
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	if !opts.CSVTitles {
+		return nil
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// JSON representation of table.
+type jsonTable struct {
+	Title   string    `json:"title"`
+	Corner  string    `json:"corner"`
+	Columns []string  `json:"columns"`
+	Rows    []jsonRow `json:"rows"`
+}
+
+type jsonRow struct {
+	Name   string        `json:"name"`
+	Values []interface{} `json:"values"`
+	// Names of columns in which row has the best value.
+	Best []string `json:"best,omitempty"`
+}
+
+// RenderJSON renders table as single line JSON object with title, corner, columns and rows (name, values and if BoldBest option is set, columns with best values).
+// Numeric values are JSON numbers (null for NaN and infinite values), text cells are JSON strings.
+func RenderJSON(w io.Writer, t *Table, opts Options) error {
+	best := t.bestCells(opts.BoldBest)
+	jt := jsonTable{Title: t.Title, Corner: t.Corner, Columns: []string{}, Rows: []jsonRow{}}
+	for _, c := range t.Columns {
+		jt.Columns = append(jt.Columns, c.Name)
+	}
+	for i, r := range t.Rows {
+		jr := jsonRow{Name: r.Name, Values: []interface{}{}}
+		for j, c := range r.Cells[:len(t.Columns)] {
+			switch {
+			case c.IsText():
+				jr.Values = append(jr.Values, c.Text)
+			case math.IsNaN(c.Value) || math.IsInf(c.Value, 0):
+				jr.Values = append(jr.Values, nil)
+			default:
+				jr.Values = append(jr.Values, c.Value)
+			}
+			if best[i][j] {
+				jr.Best = append(jr.Best, t.Columns[j].Name)
+			}
+		}
+		jt.Rows = append(jt.Rows, jr)
+	}
+	return json.NewEncoder(w).Encode(jt)
+}
+
+// Returns s with GitHub Markdown table special characters (and "<" starting raw HTML) escaped.
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "|", "\\|", "*", "\\*", "_", "\\_", "<", "\\<").Replace(s)
+}
+
+// RenderMarkdown renders table as GitHub Markdown table preceded by title, followed by empty line. Best values are bold.
+func RenderMarkdown(w io.Writer, t *Table, opts Options) error {
+	best := t.bestCells(opts.BoldBest)
+	b := &strings.Builder{}
+	for _, line := range strings.Split(t.Title, "\n") {
+		fmt.Fprintf(b, "%s  \n", escapeMarkdown(line))
+	}
+	fmt.Fprintln(b)
+
+	fmt.Fprintf(b, "| %s |", escapeMarkdown(t.Corner))
+	for _, c := range t.Columns {
+		fmt.Fprintf(b, " %s |", escapeMarkdown(c.Name))
+	}
+	fmt.Fprintln(b)
+	fmt.Fprint(b, "|:---|")
+	for range t.Columns {
+		fmt.Fprint(b, "---:|")
+	}
+	fmt.Fprintln(b)
+	for i, r := range t.Rows {
+		fmt.Fprintf(b, "| %s |", escapeMarkdown(r.Name))
+		for j, c := range r.Cells[:len(t.Columns)] {
+			s := escapeMarkdown(t.format(c, j))
+			if best[i][j] {
+				s = "**" + s + "**"
+			}
+			fmt.Fprintf(b, " %s |", s)
+		}
+		fmt.Fprintln(b)
+	}
+	fmt.Fprintln(b)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Returns s with LaTeX special characters escaped.
+func escapeLaTeX(s string) string {
+	return strings.NewReplacer(
+		"\\", "\\textbackslash{}",
+		"&", "\\&", "%", "\\%", "$", "\\$", "#", "\\#", "_", "\\_", "{", "\\{", "}", "\\}",
+		"~", "\\textasciitilde{}", "^", "\\textasciicircum{}",
+	).Replace(s)
+}
+
+// RenderLaTeX renders table as LaTeX tabular using booktabs package rules, preceded by title in comments and followed by empty line. Best values are bold.
+func RenderLaTeX(w io.Writer, t *Table, opts Options) error {
+	best := t.bestCells(opts.BoldBest)
+	b := &strings.Builder{}
+	for _, line := range strings.Split(t.Title, "\n") {
+		fmt.Fprintf(b, "%% %s\n", line)
+	}
+
+	fmt.Fprintf(b, "\\begin{tabular}{l%s}\n", strings.Repeat("r", len(t.Columns)))
+	fmt.Fprintln(b, "\\toprule")
+	fmt.Fprint(b, escapeLaTeX(t.Corner))
+	for _, c := range t.Columns {
+		fmt.Fprintf(b, " & %s", escapeLaTeX(c.Name))
+	}
+	fmt.Fprintln(b, " \\\\")
+	fmt.Fprintln(b, "\\midrule")
+	for i, r := range t.Rows {
+		fmt.Fprint(b, escapeLaTeX(r.Name))
+		for j, c := range r.Cells[:len(t.Columns)] {
+			s := escapeLaTeX(t.format(c, j))
+			if best[i][j] {
+				s = "\\textbf{" + s + "}"
+			}
+			fmt.Fprintf(b, " & %s", s)
+		}
+		fmt.Fprintln(b, " \\\\")
+	}
+	fmt.Fprintln(b, "\\bottomrule")
+	fmt.Fprintln(b, "\\end{tabular}")
+	fmt.Fprintln(b)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RenderHTML renders table as HTML table element with title as caption, followed by new line. Best values are in strong elements.
+// Numeric cells have class "num", so they can be aligned by style sheet.
+func RenderHTML(w io.Writer, t *Table, opts Options) error {
+	best := t.bestCells(opts.BoldBest)
+	b := &strings.Builder{}
+	fmt.Fprintln(b, "<table>")
+	if t.Title != "" {
+		lines := []string{}
+		for _, line := range strings.Split(t.Title, "\n") {
+			lines = append(lines, html.EscapeString(line))
+		}
+		fmt.Fprintf(b, "<caption>%s</caption>\n", strings.Join(lines, "<br>"))
+	}
+
+	fmt.Fprintf(b, "<thead><tr><th>%s</th>", html.EscapeString(t.Corner))
+	for _, c := range t.Columns {
+		fmt.Fprintf(b, "<th>%s</th>", html.EscapeString(c.Name))
+	}
+	fmt.Fprintln(b, "</tr></thead>")
+	fmt.Fprintln(b, "<tbody>")
+	for i, r := range t.Rows {
+		fmt.Fprintf(b, "<tr><th>%s</th>", html.EscapeString(r.Name))
+		for j, c := range r.Cells[:len(t.Columns)] {
+			s := html.EscapeString(t.format(c, j))
+			if best[i][j] {
+				s = "<strong>" + s + "</strong>"
+			}
+			class := ""
+			if !c.IsText() {
+				class = ` class="num"`
+			}
+			fmt.Fprintf(b, "<td%s>%s</td>", class, s)
+		}
+		fmt.Fprintln(b, "</tr>")
+	}
+	fmt.Fprintln(b, "</tbody>")
+	fmt.Fprintln(b, "</table>")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,84 @@
+package table
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// Returns table covering rendering features: multi-line title, best values of both kinds with ties, NaN, infinite and text cells and characters escaped by renderers.
+func testTable() *Table {
+	t := New("Comparing metrics & evaluators:\nsecond line with 50% | <b>", "cm\\ev",
+		Column{Name: "SROCC", Best: HighestBest},
+		Column{Name: "RMSE_fit", Best: LowestBest, Format: "%.3f"},
+		Column{Name: "dropped", Format: "%.0f"},
+	)
+	t.AddRow("PSNR", Num(0.8123), Num(math.Inf(1)), Num(0))
+	t.AddRow("MS-SSIM", Num(0.9012), Num(0.25), Num(2))
+	t.AddRow("FSIM_c", Num(0.9012), Num(math.NaN()), Text("n/a"))
+	t.AddRow("GMSD", Num(math.Inf(-1)), Num(0.5))
+	return t
+}
+
+func TestRenderersGolden(t *testing.T) {
+	for _, name := range RendererNames() {
+		b := &bytes.Buffer{}
+		if err := Renderers[name].Render(b, testTable(), Options{BoldBest: true}); err != nil {
+			t.Errorf("%s renderer error: %v", name, err)
+			continue
+		}
+
+		golden := filepath.Join("testdata", name+".golden")
+		if *update {
+			if err := os.WriteFile(golden, b.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Errorf("%s renderer: %v (run go test with -update to create golden file)", name, err)
+			continue
+		}
+		if !bytes.Equal(b.Bytes(), want) {
+			t.Errorf("%s renderer output differs from %s:\n%s\nwant:\n%s", name, golden, b.Bytes(), want)
+		}
+	}
+}
+
+func TestRenderCSVTitles(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := RenderCSV(b, testTable(), Options{CSVTitles: true}); err != nil {
+		t.Fatalf("RenderCSV error: %v", err)
+	}
+	plain, err := os.ReadFile(filepath.Join("testdata", "csv.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Comparing metrics & evaluators:\n# second line with 50% | <b>\n" + string(plain) + "\n"
+	if b.String() != want {
+		t.Errorf("RenderCSV with titles:\n%s\nwant:\n%s", b, want)
+	}
+}
+
+func TestBestRows(t *testing.T) {
+	tbl := testTable()
+	for col, want := range [][]int{{1, 2}, {1}, nil} {
+		got := tbl.BestRows(col)
+		if len(got) != len(want) {
+			t.Errorf("column %d: best rows %v, want %v", col, got, want)
+			continue
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("column %d: best rows %v, want %v", col, got, want)
+				break
+			}
+		}
+	}
+}
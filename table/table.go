@@ -0,0 +1,167 @@
+// Package table implements tables of metrics (rows) × evaluators (columns) results and their rendering to text, CSV, JSON, GitHub Markdown, LaTeX and HTML.
+package table
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Best tells which value in column is the best.
+type Best int
+
+const (
+	NoBest      Best = iota // column has no best value
+	HighestBest             // highest value is the best
+	LowestBest              // lowest value is the best
+)
+
+// Column is a table column.
+type Column struct {
+	Name string
+	Best Best
+	// Format of numeric values (fmt verb), "%f" if empty.
+	Format string
+}
+
+// Cell is a table cell with numeric value, or text if Text is not empty.
+type Cell struct {
+	Value float64
+	Text  string
+}
+
+// Num returns numeric cell with value v.
+func Num(v float64) Cell {
+	return Cell{Value: v}
+}
+
+// Text returns text cell.
+func Text(s string) Cell {
+	return Cell{Value: math.NaN(), Text: s}
+}
+
+// IsText returns true if cell is text cell.
+func (c Cell) IsText() bool {
+	return c.Text != ""
+}
+
+// Row is a named table row.
+type Row struct {
+	Name  string
+	Cells []Cell
+}
+
+// Table is a table with titled rows and columns. Title can have more lines.
+type Table struct {
+	Title   string
+	Corner  string
+	Columns []Column
+	Rows    []Row
+}
+
+// New returns table without rows.
+func New(title, corner string, columns ...Column) *Table {
+	return &Table{Title: title, Corner: corner, Columns: columns}
+}
+
+// AddRow appends row with name and cells to table. Missing cells are filled with NaN values.
+func (t *Table) AddRow(name string, cells ...Cell) {
+	for len(cells) < len(t.Columns) {
+		cells = append(cells, Num(math.NaN()))
+	}
+	t.Rows = append(t.Rows, Row{name, cells})
+}
+
+// Returns formatted cell c in column col.
+func (t *Table) format(c Cell, col int) string {
+	if c.IsText() {
+		return c.Text
+	}
+	f := t.Columns[col].Format
+	if f == "" {
+		f = "%f"
+	}
+	return fmt.Sprintf(f, c.Value)
+}
+
+// BestRows returns indexes of rows with the best numeric value in column col (more if tied). Text cells and NaN values are ignored.
+func (t *Table) BestRows(col int) []int {
+	best := t.Columns[col].Best
+	if best == NoBest {
+		return nil
+	}
+	res, bv := []int{}, math.NaN()
+	for i, r := range t.Rows {
+		c := r.Cells[col]
+		if c.IsText() || math.IsNaN(c.Value) {
+			continue
+		}
+		switch {
+		case math.IsNaN(bv), best == HighestBest && c.Value > bv, best == LowestBest && c.Value < bv:
+			res, bv = []int{i}, c.Value
+		case c.Value == bv:
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Returns best[row][col] flags of best cells, all false if bold is false.
+func (t *Table) bestCells(bold bool) [][]bool {
+	res := make([][]bool, len(t.Rows))
+	for i := range res {
+		res[i] = make([]bool, len(t.Columns))
+	}
+	if !bold {
+		return res
+	}
+	for col := range t.Columns {
+		for _, i := range t.BestRows(col) {
+			res[i][col] = true
+		}
+	}
+	return res
+}
+
+// Options of table rendering.
+type Options struct {
+	// Emphasize the best value in each column (if column has best value).
+	BoldBest bool
+	// Write title lines as comments starting with "# " before CSV header and empty line after CSV table.
+	// CSV with titles is not valid CSV, but more tables can be written to one output distinguishably.
+	CSVTitles bool
+}
+
+// Renderer renders tables to writer.
+type Renderer interface {
+	Render(w io.Writer, t *Table, opts Options) error
+}
+
+// RendererFunc is an adapter to allow the use of ordinary functions as renderers.
+type RendererFunc func(w io.Writer, t *Table, opts Options) error
+
+// Render calls f(w, t, opts).
+func (f RendererFunc) Render(w io.Writer, t *Table, opts Options) error {
+	return f(w, t, opts)
+}
+
+// Renderers contains available renderers by name.
+var Renderers = map[string]Renderer{
+	"text":     RendererFunc(RenderText),
+	"csv":      RendererFunc(RenderCSV),
+	"json":     RendererFunc(RenderJSON),
+	"markdown": RendererFunc(RenderMarkdown),
+	"latex":    RendererFunc(RenderLaTeX),
+	"html":     RendererFunc(RenderHTML),
+}
+
+// RendererNames returns sorted names of available renderers.
+func RendererNames() []string {
+	res := make([]string, 0, len(Renderers))
+	for n := range Renderers {
+		res = append(res, n)
+	}
+	sort.Strings(res)
+	return res
+}